@@ -0,0 +1,247 @@
+// Package aeadstream encrypts a stream of any length with an AEAD using the STREAM
+// construction of Hoang, Reyhanitabar, Rogaway and Vizár, so that it can be written
+// and read incrementally without ever releasing unauthenticated plaintext. The
+// AES-GCM and ChaCha20-Poly1305 streams of this module are both built on it.
+package aeadstream
+
+import (
+	"bufio"
+	"crypto/cipher"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+
+	"crypt/drbg"
+)
+
+// SegmentSize is the amount of plaintext sealed into each segment of a stream.
+const SegmentSize = 64 * 1024
+
+// NonceSize is the nonce size the AEAD must use: prefix(7) || counter(4) || last(1).
+const NonceSize = prefixSize + 5
+
+const (
+	version      byte = 1
+	prefixSize        = 7
+	headerSize        = 1 + prefixSize
+	lastSegment  byte = 1
+	innerSegment byte = 0
+	maxCounter        = math.MaxUint32
+)
+
+var (
+	// ErrInvalidStream is returned when a stream header is missing or malformed, or
+	// when a stream would need more segments than the counter can address.
+	ErrInvalidStream = errors.New("aeadstream: invalid encrypted stream")
+
+	// ErrAuthenticationFailed is returned when a segment has been modified, dropped,
+	// reordered or appended, or the stream ends without its final segment.
+	ErrAuthenticationFailed = errors.New("aeadstream: message authentication failed")
+
+	// ErrNonceSize is returned for an AEAD whose nonce is not NonceSize bytes.
+	ErrNonceSize = errors.New("aeadstream: AEAD nonce must be 12 bytes")
+
+	errClosed = errors.New("aeadstream: write to closed stream")
+)
+
+// The stream is a header version(1) || prefix(7), then segments of at most
+// SegmentSize plaintext bytes, each sealed under the nonce
+// prefix || uint32 counter || last flag. The counter stops segments being reordered
+// or dropped, the last flag stops truncation and appending at a segment boundary,
+// and the header is authenticated as associated data of every segment.
+func segmentNonce(prefix []byte, counter uint32, last byte) []byte {
+	nonce := make([]byte, 0, NonceSize)
+	nonce = append(nonce, prefix...)
+	nonce = binary.BigEndian.AppendUint32(nonce, counter)
+
+	return append(nonce, last)
+}
+
+type encrypter struct {
+	aead    cipher.AEAD
+	w       io.Writer
+	header  []byte
+	buf     []byte
+	counter uint32
+	started bool
+	closed  bool
+}
+
+// NewEncrypter returns a writer that encrypts its input incrementally with aead in
+// SegmentSize segments. Close must be called to write the final segment; it also
+// closes w if w is an io.Closer. The nonce prefix is read from rand.
+func NewEncrypter(rand io.Reader, aead cipher.AEAD, w io.Writer) (io.WriteCloser, error) {
+	if aead.NonceSize() != NonceSize {
+		return nil, ErrNonceSize
+	}
+	prefix, err := drbg.Read(rand, prefixSize)
+	if err != nil {
+		return nil, err
+	}
+
+	header := append([]byte{version}, prefix...)
+	return &encrypter{aead: aead, w: w, header: header}, nil
+}
+
+func (s *encrypter) Write(p []byte) (int, error) {
+	if s.closed {
+		return 0, errClosed
+	}
+
+	s.buf = append(s.buf, p...)
+	// keep at least one byte buffered so Close always has a final segment to flag
+	for len(s.buf) > SegmentSize {
+		if err := s.seal(s.buf[:SegmentSize], innerSegment); err != nil {
+			return 0, err
+		}
+		s.buf = s.buf[SegmentSize:]
+	}
+
+	return len(p), nil
+}
+
+func (s *encrypter) Close() error {
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+
+	if err := s.seal(s.buf, lastSegment); err != nil {
+		return err
+	}
+	s.buf = nil
+
+	if c, ok := s.w.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+func (s *encrypter) seal(segment []byte, last byte) error {
+	if !s.started {
+		if _, err := s.w.Write(s.header); err != nil {
+			return err
+		}
+		s.started = true
+	}
+	if s.counter == maxCounter && last != lastSegment {
+		return ErrInvalidStream
+	}
+
+	nonce := segmentNonce(s.header[1:], s.counter, last)
+	if _, err := s.w.Write(s.aead.Seal(nil, nonce, segment, s.header)); err != nil {
+		return err
+	}
+	s.counter++
+
+	return nil
+}
+
+type decrypter struct {
+	aead    cipher.AEAD
+	r       *bufio.Reader
+	header  []byte
+	segment []byte
+	plain   []byte
+	counter uint32
+	done    bool
+	err     error
+	authErr error
+}
+
+// authError reports a failed segment under the sentinel of the package wrapping the
+// stream while still matching ErrAuthenticationFailed.
+type authError struct {
+	err error
+}
+
+func (e authError) Error() string { return e.err.Error() }
+
+func (e authError) Unwrap() []error { return []error{e.err, ErrAuthenticationFailed} }
+
+// NewDecrypter returns a reader that authenticates and decrypts a stream produced
+// by NewEncrypter with the same AEAD. No plaintext from a segment is returned before
+// that segment has been authenticated.
+func NewDecrypter(aead cipher.AEAD, r io.Reader) (io.Reader, error) {
+	return newDecrypter(aead, r, ErrAuthenticationFailed)
+}
+
+// NewDecrypterWithError is like NewDecrypter, but a segment that fails
+// authentication is reported as an error that errors.Is matches against both
+// authErr and ErrAuthenticationFailed, so a wrapping package can keep its own sentinel.
+func NewDecrypterWithError(aead cipher.AEAD, r io.Reader, authErr error) (io.Reader, error) {
+	return newDecrypter(aead, r, authError{authErr})
+}
+
+func newDecrypter(aead cipher.AEAD, r io.Reader, authErr error) (io.Reader, error) {
+	if aead.NonceSize() != NonceSize {
+		return nil, ErrNonceSize
+	}
+
+	header := make([]byte, headerSize)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, ErrInvalidStream
+	}
+	if header[0] != version {
+		return nil, ErrInvalidStream
+	}
+
+	return &decrypter{
+		aead:    aead,
+		r:       bufio.NewReader(r),
+		header:  header,
+		segment: make([]byte, SegmentSize+aead.Overhead()),
+		authErr: authErr,
+	}, nil
+}
+
+func (s *decrypter) Read(p []byte) (int, error) {
+	for len(s.plain) == 0 {
+		if s.err != nil {
+			return 0, s.err
+		}
+		if s.done {
+			return 0, io.EOF
+		}
+		s.plain, s.err = s.open()
+	}
+
+	n := copy(p, s.plain)
+	s.plain = s.plain[n:]
+	return n, nil
+}
+
+func (s *decrypter) open() ([]byte, error) {
+	n, err := io.ReadFull(s.r, s.segment)
+	switch err {
+	case nil:
+		// a full segment is the last one only if nothing follows it
+		if _, err := s.r.Peek(1); err == io.EOF {
+			s.done = true
+		} else if err != nil {
+			return nil, err
+		}
+	case io.EOF, io.ErrUnexpectedEOF:
+		s.done = true
+	default:
+		return nil, err
+	}
+
+	last := innerSegment
+	if s.done {
+		last = lastSegment
+	}
+	if !s.done && s.counter == maxCounter {
+		return nil, ErrInvalidStream
+	}
+
+	nonce := segmentNonce(s.header[1:], s.counter, last)
+	plain, err := s.aead.Open(nil, nonce, s.segment[:n], s.header)
+	if err != nil {
+		return nil, s.authErr
+	}
+	s.counter++
+
+	return plain, nil
+}
@@ -0,0 +1,176 @@
+package aeadstream
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"errors"
+	"io"
+	"testing"
+
+	"crypt/drbg"
+
+	"golang.org/x/crypto/chacha20poly1305"
+)
+
+func testAEADs(t *testing.T) map[string]cipher.AEAD {
+	block, err := aes.NewCipher(make([]byte, 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	chacha, err := chacha20poly1305.New(make([]byte, chacha20poly1305.KeySize))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return map[string]cipher.AEAD{"AES-GCM": gcm, "ChaCha20-Poly1305": chacha}
+}
+
+func encrypt(t *testing.T, aead cipher.AEAD, plaintext []byte) []byte {
+	var buf bytes.Buffer
+	w, err := NewEncrypter(drbg.NewDeterministic([]byte("aeadstream")), aead, &buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// write in pieces that do not line up with segments
+	for len(plaintext) > 0 {
+		n := min(len(plaintext), 1000)
+		if _, err := w.Write(plaintext[:n]); err != nil {
+			t.Fatal(err)
+		}
+		plaintext = plaintext[n:]
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.Bytes()
+}
+
+func decrypt(aead cipher.AEAD, stream []byte) ([]byte, error) {
+	r, err := NewDecrypter(aead, bytes.NewReader(stream))
+	if err != nil {
+		return nil, err
+	}
+
+	return io.ReadAll(r)
+}
+
+// segments splits a stream into its header and sealed segments.
+func segments(aead cipher.AEAD, stream []byte) (header []byte, sealed [][]byte) {
+	header, stream = stream[:headerSize], stream[headerSize:]
+	for size := SegmentSize + aead.Overhead(); len(stream) > size; stream = stream[size:] {
+		sealed = append(sealed, stream[:size])
+	}
+
+	return header, append(sealed, stream)
+}
+
+func join(header []byte, sealed ...[]byte) []byte {
+	return bytes.Join(append([][]byte{header}, sealed...), nil)
+}
+
+func TestRoundTrip(t *testing.T) {
+	plaintext, _ := drbg.Read(drbg.NewDeterministic([]byte("plaintext")), 3*SegmentSize)
+	for name, aead := range testAEADs(t) {
+		for _, size := range []int{0, 1, SegmentSize - 1, SegmentSize, SegmentSize + 1, 2*SegmentSize + 7} {
+			got, err := decrypt(aead, encrypt(t, aead, plaintext[:size]))
+			if err != nil || !bytes.Equal(got, plaintext[:size]) {
+				t.Errorf("%s, %d bytes: round trip failed: %v", name, size, err)
+			}
+		}
+	}
+}
+
+func TestTamperedStreams(t *testing.T) {
+	plaintext := bytes.Repeat([]byte("stream"), SegmentSize) // six segments
+	for name, aead := range testAEADs(t) {
+		stream := encrypt(t, aead, plaintext)
+		header, sealed := segments(aead, stream)
+		if len(sealed) != 6 {
+			t.Fatalf("%s: got %d segments, want 6", name, len(sealed))
+		}
+
+		otherHeader := bytes.Clone(header)
+		otherHeader[1] ^= 1
+		cases := map[string][]byte{
+			"truncated at a segment boundary": join(header, sealed[:5]...),
+			"truncated inside a segment":      stream[:len(stream)-10],
+			"header only":                     header,
+			"segments reordered":              join(header, sealed[1], sealed[0], sealed[2], sealed[3], sealed[4], sealed[5]),
+			"segment dropped":                 join(header, append(sealed[:2:2], sealed[3:]...)...),
+			"segment duplicated":              join(header, append(sealed[:3:3], sealed[2:]...)...),
+			"byte appended":                   append(bytes.Clone(stream), 0),
+			"segment appended":                join(header, append(sealed, sealed[0])...),
+			"prefix changed":                  join(otherHeader, sealed...),
+		}
+		for tamper, tampered := range cases {
+			if _, err := decrypt(aead, tampered); err != ErrAuthenticationFailed {
+				t.Errorf("%s, %s: got %v, want ErrAuthenticationFailed", name, tamper, err)
+			}
+		}
+
+		badVersion := bytes.Clone(stream)
+		badVersion[0] = 2
+		if _, err := decrypt(aead, badVersion); err != ErrInvalidStream {
+			t.Errorf("%s, version changed: got %v, want ErrInvalidStream", name, err)
+		}
+		if _, err := decrypt(aead, header[:3]); err != ErrInvalidStream {
+			t.Errorf("%s, short header: got %v, want ErrInvalidStream", name, err)
+		}
+	}
+}
+
+func TestNonceSize(t *testing.T) {
+	block, err := aes.NewCipher(make([]byte, 16))
+	if err != nil {
+		t.Fatal(err)
+	}
+	gcm, err := cipher.NewGCMWithNonceSize(block, 16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewEncrypter(nil, gcm, io.Discard); err != ErrNonceSize {
+		t.Errorf("NewEncrypter: got %v, want ErrNonceSize", err)
+	}
+	if _, err := NewDecrypter(gcm, bytes.NewReader(nil)); err != ErrNonceSize {
+		t.Errorf("NewDecrypter: got %v, want ErrNonceSize", err)
+	}
+}
+
+func TestWriteAfterClose(t *testing.T) {
+	aead := testAEADs(t)["AES-GCM"]
+	w, err := NewEncrypter(nil, aead, io.Discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Write([]byte("late")); err == nil {
+		t.Error("Write after Close did not fail")
+	}
+}
+
+func TestDecrypterWithError(t *testing.T) {
+	aead, err := chacha20poly1305.New(make([]byte, 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+	stream := encrypt(t, aead, []byte("wrapped"))
+	stream[len(stream)-1] ^= 1
+
+	own := errors.New("pkg: message authentication failed")
+	r, err := NewDecrypterWithError(aead, bytes.NewReader(stream), own)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = io.ReadAll(r)
+	if !errors.Is(err, own) || !errors.Is(err, ErrAuthenticationFailed) || err.Error() != own.Error() {
+		t.Errorf("got %v, want an error matching both sentinels", err)
+	}
+}
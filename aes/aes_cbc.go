@@ -2,52 +2,71 @@ package aes
 
 import (
 	"crypto/aes"
-	"crypto/cipher"
 	"fmt"
+
+	"crypt/blockcipher"
+	"crypt/drbg"
 )
 
 func AES_CBC() {
-	key := generateAESKey(32)
+	keySize := AES256
+	key, err := NewKey(keySize)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println("key size: ", keySize)
 	fmt.Println("key(bytes): ", key)
 	fmt.Println("key(string): ", string(key))
 
-	iv := generateIV(aes.BlockSize)
+	iv, err := drbg.Read(nil, aes.BlockSize)
+	if err != nil {
+		panic(err)
+	}
 	fmt.Println("iv(bytes): ", iv)
 	fmt.Println("iv(string): ", string(iv))
 
 	plainText := "Hello this is mustafa!!!! and I am testing aes encryption using ecb mode \nHello this is mustafa!!! and I am testing aes encryption using ecb mode \nI repeated same sentence twice just to see the similarity of ciphertext"
-	cipher := aes_cbc_encrypt([]byte(plainText), key, iv)
+	cipher, err := EncryptCBC(key, iv, []byte(plainText))
+	if err != nil {
+		panic(err)
+	}
 	fmt.Println("cipherText(bytes): ", cipher)
 	fmt.Println("cipherText(string): ", string(cipher))
 
-	plain := aes_cbc_decrypt(cipher, key, iv)
+	plain, err := DecryptCBC(key, iv, cipher)
+	if err != nil {
+		panic(err)
+	}
 	fmt.Println("decryptedText(bytes): ", plain)
 	fmt.Println("decryptedText(string): ", string(plain))
 }
 
-func aes_cbc_encrypt(data, key, iv []byte) []byte {
-	blck, err := aes.NewCipher(key)
+// EncryptCBC pads plaintext with PKCS#7 and encrypts it in CBC mode.
+func EncryptCBC(key, iv, plaintext []byte) ([]byte, error) {
+	return EncryptCBCWithPadding(key, iv, plaintext, PKCS7)
+}
+
+// DecryptCBC decrypts a CBC ciphertext produced by EncryptCBC and removes the padding.
+func DecryptCBC(key, iv, ciphertext []byte) ([]byte, error) {
+	return DecryptCBCWithPadding(key, iv, ciphertext, PKCS7)
+}
+
+// EncryptCBCWithPadding pads plaintext with the given scheme and encrypts it in CBC mode.
+func EncryptCBCWithPadding(key, iv, plaintext []byte, padding Padding) ([]byte, error) {
+	blck, err := newBlock(key)
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
 
-	data = pkcs7Pad(data, aes.BlockSize)
-	mode := cipher.NewCBCEncrypter(blck, iv)
-	cipherText := make([]byte, len(data))
-	mode.CryptBlocks(cipherText, data)
-
-	return cipherText
+	return blockcipher.EncryptCBC(blck, iv, plaintext, padding)
 }
 
-func aes_cbc_decrypt(data, key, iv []byte) []byte {
-	blck, err := aes.NewCipher(key)
+// DecryptCBCWithPadding decrypts a CBC ciphertext and removes the given padding scheme.
+func DecryptCBCWithPadding(key, iv, ciphertext []byte, padding Padding) ([]byte, error) {
+	blck, err := newBlock(key)
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
 
-	mode := cipher.NewCBCDecrypter(blck, iv)
-	plainText := make([]byte, len(data))
-	mode.CryptBlocks(plainText, data)
-
-	return pkcs7Unpad(plainText, aes.BlockSize)
+	return blockcipher.DecryptCBC(blck, iv, ciphertext, padding)
 }
@@ -0,0 +1,119 @@
+package aes
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"errors"
+)
+
+// CTSVariant selects how CBC with ciphertext stealing orders the last two blocks
+// (NIST SP 800-38A addendum).
+type CTSVariant int
+
+const (
+	// CS1 keeps the partial penultimate block before the final full block.
+	CS1 CTSVariant = iota + 1
+	// CS2 swaps the last two blocks only when the plaintext is not block aligned.
+	CS2
+	// CS3 always swaps the last two blocks; it is the variant used by Kerberos (RFC 3962).
+	CS3
+)
+
+var (
+	// ErrCTSInputTooShort is returned when the input to ciphertext stealing is shorter than one block.
+	ErrCTSInputTooShort = errors.New("aes: ciphertext stealing needs at least one full block")
+
+	// ErrUnknownCTSVariant is returned for a CTSVariant that is not CS1, CS2 or CS3.
+	ErrUnknownCTSVariant = errors.New("aes: unknown ciphertext stealing variant")
+)
+
+// EncryptCBCCTS encrypts plaintext in CBC mode with ciphertext stealing, so the
+// ciphertext has exactly the length of the plaintext, which must be at least 16 bytes.
+func EncryptCBCCTS(key, iv, plaintext []byte, variant CTSVariant) ([]byte, error) {
+	blck, err := ctsSetup(key, iv, len(plaintext), variant)
+	if err != nil {
+		return nil, err
+	}
+
+	n := (len(plaintext) + aes.BlockSize - 1) / aes.BlockSize
+	d := len(plaintext) - (n-1)*aes.BlockSize // bytes in the last block, 1 to 16
+
+	padded := make([]byte, n*aes.BlockSize)
+	copy(padded, plaintext)
+	cipher.NewCBCEncrypter(blck, iv).CryptBlocks(padded, padded)
+	if n == 1 {
+		return padded, nil
+	}
+
+	// CS1 form: C1 .. C(n-2) || MSB_d(C(n-1)) || Cn
+	cipherText := make([]byte, 0, len(plaintext))
+	cipherText = append(cipherText, padded[:(n-2)*aes.BlockSize]...)
+	penultimate := padded[(n-2)*aes.BlockSize : (n-2)*aes.BlockSize+d]
+	last := padded[(n-1)*aes.BlockSize:]
+
+	if variant == CS3 || (variant == CS2 && d != aes.BlockSize) {
+		cipherText = append(cipherText, last...)
+		return append(cipherText, penultimate...), nil
+	}
+	cipherText = append(cipherText, penultimate...)
+	return append(cipherText, last...), nil
+}
+
+// DecryptCBCCTS decrypts a ciphertext produced by EncryptCBCCTS with the same variant.
+func DecryptCBCCTS(key, iv, ciphertext []byte, variant CTSVariant) ([]byte, error) {
+	blck, err := ctsSetup(key, iv, len(ciphertext), variant)
+	if err != nil {
+		return nil, err
+	}
+
+	n := (len(ciphertext) + aes.BlockSize - 1) / aes.BlockSize
+	d := len(ciphertext) - (n-1)*aes.BlockSize
+	if n == 1 {
+		plainText := make([]byte, aes.BlockSize)
+		cipher.NewCBCDecrypter(blck, iv).CryptBlocks(plainText, ciphertext)
+		return plainText, nil
+	}
+
+	head := ciphertext[:(n-2)*aes.BlockSize]
+	tail := ciphertext[(n-2)*aes.BlockSize:]
+	var penultimate, last []byte
+	if variant == CS3 || (variant == CS2 && d != aes.BlockSize) {
+		last, penultimate = tail[:aes.BlockSize], tail[aes.BlockSize:]
+	} else {
+		penultimate, last = tail[:d], tail[d:]
+	}
+
+	// D(Cn) = C(n-1) xor (Pn || 0), so its low bytes complete the stolen C(n-1)
+	z := make([]byte, aes.BlockSize)
+	blck.Decrypt(z, last)
+	full := make([]byte, 0, n*aes.BlockSize)
+	full = append(full, head...)
+	full = append(full, penultimate...)
+	full = append(full, z[d:]...)
+
+	plainText := make([]byte, len(ciphertext))
+	cipher.NewCBCDecrypter(blck, iv).CryptBlocks(plainText[:(n-1)*aes.BlockSize], full)
+	for i := 0; i < d; i++ {
+		plainText[(n-1)*aes.BlockSize+i] = z[i] ^ penultimate[i]
+	}
+
+	return plainText, nil
+}
+
+func ctsSetup(key, iv []byte, length int, variant CTSVariant) (cipher.Block, error) {
+	if variant != CS1 && variant != CS2 && variant != CS3 {
+		return nil, ErrUnknownCTSVariant
+	}
+	blck, err := newBlock(key)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkIV(iv, aes.BlockSize); err != nil {
+		return nil, err
+	}
+	if length < aes.BlockSize {
+		return nil, ErrCTSInputTooShort
+	}
+
+	return blck, nil
+}
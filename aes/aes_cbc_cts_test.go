@@ -0,0 +1,88 @@
+package aes
+
+import (
+	"bytes"
+	"crypto/cipher"
+	"testing"
+)
+
+// ctsPlaintext is the input of the RFC 3962 appendix B vectors, which each use a prefix of it.
+const ctsPlaintext = "I would like the General Gau's Chicken, please, and wonton soup."
+
+// TestCBCCTSVectors are the AES-128 vectors of RFC 3962 appendix B, which uses CS3.
+func TestCBCCTSVectors(t *testing.T) {
+	key := []byte("chicken teriyaki")
+	iv := make([]byte, 16)
+	cases := []struct {
+		len    int
+		output string
+	}{
+		{17, "c6353568f2bf8cb4d8a580362da7ff7f 97"},
+		{31, "fc00783e0efdb2c1d445d4c8eff7ed22 97687268d6ecccc0c07b25e25ecfe5"},
+		{32, "39312523a78662d5be7fcbcc98ebf5a8 97687268d6ecccc0c07b25e25ecfe584"},
+		{47, "97687268d6ecccc0c07b25e25ecfe584 b3fffd940c16a18c1b5549d2f838029e 39312523a78662d5be7fcbcc98ebf5"},
+		{48, "97687268d6ecccc0c07b25e25ecfe584 9dad8bbb96c4cdc03bc103e1a194bbd8 39312523a78662d5be7fcbcc98ebf5a8"},
+		{64, "97687268d6ecccc0c07b25e25ecfe584 39312523a78662d5be7fcbcc98ebf5a8 " +
+			"4807efe836ee89a526730dbc2f7bc840 9dad8bbb96c4cdc03bc103e1a194bbd8"},
+	}
+
+	for _, c := range cases {
+		plaintext, want := []byte(ctsPlaintext[:c.len]), decodeHex(t, c.output)
+		got, err := EncryptCBCCTS(key, iv, plaintext, CS3)
+		if err != nil || !bytes.Equal(got, want) {
+			t.Errorf("len %d: got %x, %v, want %x", c.len, got, err, want)
+		}
+		if decrypted, err := DecryptCBCCTS(key, iv, want, CS3); err != nil || !bytes.Equal(decrypted, plaintext) {
+			t.Errorf("len %d: DecryptCBCCTS = %q, %v", c.len, decrypted, err)
+		}
+	}
+}
+
+func TestCBCCTSVariants(t *testing.T) {
+	key := []byte("chicken teriyaki")
+	iv := []byte("0123456789abcdef")
+	block, err := newBlock(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, n := range []int{16, 17, 31, 32, 33, 47, 48, 64} {
+		plaintext := []byte(ctsPlaintext[:n])
+		var out [CS3 + 1][]byte
+		for _, v := range []CTSVariant{CS1, CS2, CS3} {
+			ct, err := EncryptCBCCTS(key, iv, plaintext, v)
+			if err != nil || len(ct) != n {
+				t.Fatalf("len %d, CS%d: got %d bytes, %v", n, v, len(ct), err)
+			}
+			pt, err := DecryptCBCCTS(key, iv, ct, v)
+			if err != nil || !bytes.Equal(pt, plaintext) {
+				t.Errorf("len %d, CS%d: round trip = %q, %v", n, v, pt, err)
+			}
+			out[v] = ct
+		}
+
+		if n%16 == 0 {
+			// aligned input: CS1 and CS2 are plain CBC
+			cbc := make([]byte, n)
+			cipher.NewCBCEncrypter(block, iv).CryptBlocks(cbc, plaintext)
+			if !bytes.Equal(out[CS1], cbc) || !bytes.Equal(out[CS2], cbc) {
+				t.Errorf("len %d: CS1 or CS2 differs from CBC", n)
+			}
+		} else if !bytes.Equal(out[CS2], out[CS3]) {
+			t.Errorf("len %d: CS2 differs from CS3 on unaligned input", n)
+		}
+	}
+}
+
+func TestCBCCTSErrors(t *testing.T) {
+	key, iv := make([]byte, 16), make([]byte, 16)
+	if _, err := EncryptCBCCTS(key, iv, make([]byte, 15), CS3); err != ErrCTSInputTooShort {
+		t.Errorf("15 bytes: got %v, want ErrCTSInputTooShort", err)
+	}
+	if _, err := DecryptCBCCTS(key, iv, make([]byte, 32), CTSVariant(4)); err != ErrUnknownCTSVariant {
+		t.Errorf("variant 4: got %v, want ErrUnknownCTSVariant", err)
+	}
+	if _, err := EncryptCBCCTS(key, iv[:8], make([]byte, 32), CS1); err != IVSizeError(8) {
+		t.Errorf("8-byte IV: got %v, want IVSizeError(8)", err)
+	}
+}
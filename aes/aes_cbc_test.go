@@ -0,0 +1,71 @@
+package aes
+
+import (
+	"bytes"
+	"crypto/cipher"
+	"testing"
+)
+
+// TestMalformedCiphertext checks that CBC and ECB decryption report malformed input
+// as errors instead of panicking.
+func TestMalformedCiphertext(t *testing.T) {
+	key, iv := make([]byte, 32), make([]byte, 16)
+	block, err := newBlock(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, n := range []int{0, 1, 15, 17, 31} {
+		if _, err := DecryptCBC(key, iv, make([]byte, n)); err != ErrInvalidCiphertext {
+			t.Errorf("CBC, %d bytes: got %v, want ErrInvalidCiphertext", n, err)
+		}
+		if _, err := DecryptECB(key, make([]byte, n)); err != ErrInvalidCiphertext {
+			t.Errorf("ECB, %d bytes: got %v, want ErrInvalidCiphertext", n, err)
+		}
+	}
+	if _, err := DecryptCBC(key, iv[:8], make([]byte, 16)); err != IVSizeError(8) {
+		t.Errorf("CBC, 8-byte IV: got %v, want IVSizeError(8)", err)
+	}
+
+	// whole blocks whose last plaintext block is not valid PKCS#7
+	badPadding := [][]byte{
+		bytes.Repeat([]byte{0}, 16),
+		bytes.Repeat([]byte{17}, 16),
+		append(bytes.Repeat([]byte{'a'}, 13), 1, 2, 3),
+	}
+	for _, plaintext := range badPadding {
+		ct := make([]byte, len(plaintext))
+		cipher.NewCBCEncrypter(block, iv).CryptBlocks(ct, plaintext)
+		if _, err := DecryptCBC(key, iv, ct); err != ErrInvalidPadding {
+			t.Errorf("CBC, plaintext %x: got %v, want ErrInvalidPadding", plaintext, err)
+		}
+
+		block.Encrypt(ct, plaintext)
+		if _, err := DecryptECB(key, ct); err != ErrInvalidPadding {
+			t.Errorf("ECB, plaintext %x: got %v, want ErrInvalidPadding", plaintext, err)
+		}
+	}
+}
+
+func TestPaddedRoundTrip(t *testing.T) {
+	key, iv := make([]byte, 16), make([]byte, 16)
+	for _, n := range []int{0, 1, 15, 16, 17, 33} {
+		plaintext := bytes.Repeat([]byte{'p'}, n)
+
+		ct, err := EncryptECB(key, plaintext)
+		if err != nil || len(ct)%16 != 0 || len(ct) <= n {
+			t.Fatalf("ECB, %d bytes: got %d bytes, %v", n, len(ct), err)
+		}
+		if pt, err := DecryptECB(key, ct); err != nil || !bytes.Equal(pt, plaintext) {
+			t.Errorf("ECB, %d bytes: got %q, %v", n, pt, err)
+		}
+
+		ct, err = EncryptCBC(key, iv, plaintext)
+		if err != nil {
+			t.Fatalf("CBC, %d bytes: %v", n, err)
+		}
+		if pt, err := DecryptCBC(key, iv, ct); err != nil || !bytes.Equal(pt, plaintext) {
+			t.Errorf("CBC, %d bytes: got %q, %v", n, pt, err)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package aes
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/subtle"
+	"encoding/binary"
+	"errors"
+)
+
+// ErrCCMTagSize is returned for a CCM tag length that is not an even number from 4 to 16.
+var ErrCCMTagSize = errors.New("aes: invalid CCM tag size")
+
+// ccm implements AES-CCM (NIST SP 800-38C, RFC 3610).
+type ccm struct {
+	block     cipher.Block
+	nonceSize int
+	tagSize   int
+	keySize   KeySize
+}
+
+// NewCCM returns a cipher.AEAD implementing AES-CCM. nonceSize must be between 7 and
+// 13 bytes and limits the message to 2^(8*(15-nonceSize)) - 1 bytes; tagSize must be
+// 4, 6, 8, 10, 12, 14 or 16.
+func NewCCM(key []byte, nonceSize, tagSize int) (cipher.AEAD, error) {
+	block, err := newBlock(key)
+	if err != nil {
+		return nil, err
+	}
+	if nonceSize < 7 || nonceSize > 13 {
+		return nil, IVSizeError(nonceSize)
+	}
+	if tagSize < 4 || tagSize > 16 || tagSize%2 != 0 {
+		return nil, ErrCCMTagSize
+	}
+
+	return &ccm{block: block, nonceSize: nonceSize, tagSize: tagSize, keySize: KeySize(len(key))}, nil
+}
+
+func (c *ccm) KeySize() KeySize { return c.keySize }
+
+func (c *ccm) NonceSize() int { return c.nonceSize }
+
+func (c *ccm) Overhead() int { return c.tagSize }
+
+// maxLength is the largest message length the length field can encode.
+func (c *ccm) maxLength() uint64 {
+	l := 15 - c.nonceSize
+	if l >= 8 {
+		return 1<<63 - 1
+	}
+	return 1<<(8*l) - 1
+}
+
+func (c *ccm) Seal(dst, nonce, plaintext, additionalData []byte) []byte {
+	if len(nonce) != c.nonceSize {
+		panic("aes: incorrect nonce length given to CCM")
+	}
+	if uint64(len(plaintext)) > c.maxLength() {
+		panic("aes: message too large for CCM nonce size")
+	}
+
+	tag := c.mac(nonce, plaintext, additionalData)
+	ciphertext := make([]byte, len(plaintext))
+	s0 := c.ctr(nonce, ciphertext, plaintext)
+	subtle.XORBytes(tag, tag, s0)
+
+	dst = append(dst, ciphertext...)
+	return append(dst, tag...)
+}
+
+func (c *ccm) Open(dst, nonce, ciphertext, additionalData []byte) ([]byte, error) {
+	if len(nonce) != c.nonceSize {
+		panic("aes: incorrect nonce length given to CCM")
+	}
+	if len(ciphertext) < c.tagSize || uint64(len(ciphertext)-c.tagSize) > c.maxLength() {
+		return nil, ErrAuthenticationFailed
+	}
+
+	tag := ciphertext[len(ciphertext)-c.tagSize:]
+	ciphertext = ciphertext[:len(ciphertext)-c.tagSize]
+
+	plaintext := make([]byte, len(ciphertext))
+	s0 := c.ctr(nonce, plaintext, ciphertext)
+	expected := c.mac(nonce, plaintext, additionalData)
+	subtle.XORBytes(expected, expected, s0)
+
+	if subtle.ConstantTimeCompare(tag, expected) != 1 {
+		return nil, ErrAuthenticationFailed
+	}
+	return append(dst, plaintext...), nil
+}
+
+// ctr encrypts src into dst with counter blocks A_1, A_2, ... and returns the first
+// tagSize bytes of S_0 = E(A_0) used to mask the tag.
+func (c *ccm) ctr(nonce, dst, src []byte) []byte {
+	var a [aes.BlockSize]byte
+	a[0] = byte(14 - c.nonceSize) // L - 1
+	copy(a[1:], nonce)
+
+	s0 := make([]byte, aes.BlockSize)
+	c.block.Encrypt(s0, a[:])
+
+	a[aes.BlockSize-1] = 1
+	cipher.NewCTR(c.block, a[:]).XORKeyStream(dst, src)
+
+	return s0[:c.tagSize]
+}
+
+// mac computes the CBC-MAC T over B_0, the encoded associated data and the plaintext.
+func (c *ccm) mac(nonce, plaintext, additionalData []byte) []byte {
+	var b0 [aes.BlockSize]byte
+	b0[0] = byte((c.tagSize-2)/2<<3 | (14 - c.nonceSize))
+	if len(additionalData) > 0 {
+		b0[0] |= 1 << 6
+	}
+	copy(b0[1:], nonce)
+
+	var length [8]byte
+	binary.BigEndian.PutUint64(length[:], uint64(len(plaintext)))
+	copy(b0[1+c.nonceSize:], length[8-(15-c.nonceSize):])
+
+	x := make([]byte, aes.BlockSize)
+	c.block.Encrypt(x, b0[:])
+
+	if len(additionalData) > 0 {
+		var header []byte
+		switch n := uint64(len(additionalData)); {
+		case n < 1<<16-1<<8:
+			header = binary.BigEndian.AppendUint16(nil, uint16(n))
+		case n <= 1<<32-1:
+			header = binary.BigEndian.AppendUint32([]byte{0xff, 0xfe}, uint32(n))
+		default:
+			header = binary.BigEndian.AppendUint64([]byte{0xff, 0xff}, n)
+		}
+		c.cbcMAC(x, append(header, additionalData...))
+	}
+	c.cbcMAC(x, plaintext)
+
+	return x[:c.tagSize]
+}
+
+// cbcMAC absorbs data zero-padded to whole blocks into the running CBC-MAC value x.
+func (c *ccm) cbcMAC(x, data []byte) {
+	for len(data) > 0 {
+		n := subtle.XORBytes(x, x, data)
+		data = data[n:]
+		c.block.Encrypt(x, x)
+	}
+}
@@ -0,0 +1,63 @@
+package aes
+
+import (
+	"bytes"
+	"testing"
+)
+
+// TestCCMVectors are packet vectors #1 and #2 of RFC 3610 section 8; the first
+// eight bytes of each packet are the associated data.
+func TestCCMVectors(t *testing.T) {
+	key := decodeHex(t, "C0 C1 C2 C3 C4 C5 C6 C7 C8 C9 CA CB CC CD CE CF")
+	cases := []struct {
+		nonce, packet, output string
+	}{
+		{
+			"00 00 00 03 02 01 00 A0 A1 A2 A3 A4 A5",
+			"00 01 02 03 04 05 06 07 08 09 0A 0B 0C 0D 0E 0F 10 11 12 13 14 15 16 17 18 19 1A 1B 1C 1D 1E",
+			"00 01 02 03 04 05 06 07 58 8C 97 9A 61 C6 63 D2 F0 66 D0 C2 C0 F9 89 80 6D 5F 6B 61 DA C3 84 17 E8 D1 2C FD F9 26 E0",
+		},
+		{
+			"00 00 00 04 03 02 01 A0 A1 A2 A3 A4 A5",
+			"00 01 02 03 04 05 06 07 08 09 0A 0B 0C 0D 0E 0F 10 11 12 13 14 15 16 17 18 19 1A 1B 1C 1D 1E 1F",
+			"00 01 02 03 04 05 06 07 72 C9 1A 36 E1 35 F8 CF 29 1C A8 94 08 5C 87 E3 CC 15 C4 39 C9 E4 3A 3B A0 91 D5 6E 10 40 09 16",
+		},
+	}
+
+	aead, err := NewCCM(key, 13, 8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, c := range cases {
+		nonce, packet, output := decodeHex(t, c.nonce), decodeHex(t, c.packet), decodeHex(t, c.output)
+		ad, want := packet[:8], output[8:]
+
+		got := aead.Seal(nil, nonce, packet[8:], ad)
+		if !bytes.Equal(got, want) {
+			t.Errorf("packet %d: got %X, want %X", i+1, got, want)
+		}
+		plaintext, err := aead.Open(nil, nonce, want, ad)
+		if err != nil || !bytes.Equal(plaintext, packet[8:]) {
+			t.Errorf("packet %d: Open = %X, %v", i+1, plaintext, err)
+		}
+
+		want[len(want)-1] ^= 1
+		if _, err := aead.Open(nil, nonce, want, ad); err == nil {
+			t.Errorf("packet %d: tampered tag accepted", i+1)
+		}
+	}
+}
+
+func TestCCMParams(t *testing.T) {
+	key := make([]byte, 16)
+	for _, n := range []int{6, 14} {
+		if _, err := NewCCM(key, n, 16); err != IVSizeError(n) {
+			t.Errorf("nonce size %d: got %v, want IVSizeError", n, err)
+		}
+	}
+	for _, tag := range []int{2, 5, 18} {
+		if _, err := NewCCM(key, 12, tag); err != ErrCCMTagSize {
+			t.Errorf("tag size %d: got %v, want ErrCCMTagSize", tag, err)
+		}
+	}
+}
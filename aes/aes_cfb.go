@@ -2,49 +2,61 @@ package aes
 
 import (
 	"crypto/aes"
-	"crypto/cipher"
 	"fmt"
+
+	"crypt/blockcipher"
+	"crypt/drbg"
 )
 
 func AES_CFB() {
-	key := generateAESKey(32)
+	keySize := AES256
+	key, err := NewKey(keySize)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println("key size: ", keySize)
 	fmt.Println("key(bytes): ", key)
 	fmt.Println("key(string): ", string(key))
 
-	iv := generateIV(aes.BlockSize)
+	iv, err := drbg.Read(nil, aes.BlockSize)
+	if err != nil {
+		panic(err)
+	}
 	fmt.Println("iv(bytes): ", iv)
 	fmt.Println("iv(string): ", string(iv))
 
 	plainText := "Hello this is mustafa!!!! and I am testing aes encryption using ecb mode \nHello this is mustafa!!! and I am testing aes encryption using ecb mode \nI repeated same sentence twice just to see the similarity of ciphertext"
-	cipher := aes_cfb_encrypt([]byte(plainText), key, iv)
+	cipher, err := EncryptCFB(key, iv, []byte(plainText))
+	if err != nil {
+		panic(err)
+	}
 	fmt.Println("cipherText(bytes): ", cipher)
 	fmt.Println("cipherText(string): ", string(cipher))
 
-	plain := aes_cfb_decrypt(cipher, key, iv)
+	plain, err := DecryptCFB(key, iv, cipher)
+	if err != nil {
+		panic(err)
+	}
 	fmt.Println("decryptedText(bytes): ", plain)
 	fmt.Println("decryptedText(string): ", string(plain))
 }
 
-func aes_cfb_encrypt(plaintext, key, iv []byte) []byte {
-	block, err := aes.NewCipher(key)
+// EncryptCFB encrypts plaintext in CFB mode.
+func EncryptCFB(key, iv, plaintext []byte) ([]byte, error) {
+	block, err := newBlock(key)
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
-	ciphertext := make([]byte, len(plaintext))
-	stream := cipher.NewCFBEncrypter(block, iv)
-	stream.XORKeyStream(ciphertext, plaintext)
 
-	return ciphertext
+	return blockcipher.EncryptCFB(block, iv, plaintext)
 }
 
-func aes_cfb_decrypt(ciphertext, key, iv []byte) []byte {
-	block, err := aes.NewCipher(key)
+// DecryptCFB decrypts a ciphertext produced by EncryptCFB.
+func DecryptCFB(key, iv, ciphertext []byte) ([]byte, error) {
+	block, err := newBlock(key)
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
-	plaintext := make([]byte, len(ciphertext))
-	stream := cipher.NewCFBDecrypter(block, iv)
-	stream.XORKeyStream(plaintext, ciphertext)
 
-	return plaintext
+	return blockcipher.DecryptCFB(block, iv, ciphertext)
 }
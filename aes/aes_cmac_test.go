@@ -0,0 +1,48 @@
+package aes
+
+import (
+	"bytes"
+	"testing"
+)
+
+// TestCMACVectors are the AES-128 examples of RFC 4493 section 4.
+func TestCMACVectors(t *testing.T) {
+	key := decodeHex(t, "2b7e1516 28aed2a6 abf71588 09cf4f3c")
+	message := decodeHex(t, "6bc1bee2 2e409f96 e93d7e11 7393172a"+
+		"ae2d8a57 1e03ac9c 9eb76fac 45af8e51"+
+		"30c81c46 a35ce411 e5fbc119 1a0a52ef"+
+		"f69f2445 df4f9b17 ad2b417b e66c3710")
+
+	cases := []struct {
+		len int
+		mac string
+	}{
+		{0, "bb1d6929 e9593728 7fa37d12 9b756746"},
+		{16, "070a16b4 6b4d4144 f79bdd9d d04a287c"},
+		{40, "dfa66747 de9ae630 30ca3261 1497c827"},
+		{64, "51f0bebf 7e3b9d92 fc497417 79363cfe"},
+	}
+
+	h, err := NewCMAC(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range cases {
+		want := decodeHex(t, c.mac)
+
+		h.Reset()
+		h.Write(message[:c.len])
+		if got := h.Sum(nil); !bytes.Equal(got, want) {
+			t.Errorf("len %d: got %x, want %x", c.len, got, want)
+		}
+
+		// the same message written one byte at a time
+		h.Reset()
+		for _, b := range message[:c.len] {
+			h.Write([]byte{b})
+		}
+		if got := h.Sum(nil); !bytes.Equal(got, want) {
+			t.Errorf("len %d, bytewise: got %x, want %x", c.len, got, want)
+		}
+	}
+}
@@ -2,41 +2,56 @@ package aes
 
 import (
 	"crypto/aes"
-	"crypto/cipher"
 	"fmt"
+
+	"crypt/blockcipher"
+	"crypt/drbg"
 )
 
 func AES_CTR() {
-	key := generateAESKey(32)
+	keySize := AES256
+	key, err := NewKey(keySize)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println("key size: ", keySize)
 	fmt.Println("key(bytes): ", key)
 	fmt.Println("key(string): ", string(key))
 
-	nounce := generateIV(aes.BlockSize)
+	nounce, err := drbg.Read(nil, aes.BlockSize)
+	if err != nil {
+		panic(err)
+	}
 	fmt.Println("iv(bytes): ", nounce)
 	fmt.Println("iv(string): ", string(nounce))
 
 	plainText := "Hello this is mustafa!!!! and I am testing aes encryption using ecb mode \nHello this is mustafa!!! and I am testing aes encryption using ecb mode \nI repeated same sentence twice just to see the similarity of ciphertext"
-	cipher := aes_ctr_encrypt([]byte(plainText), key, nounce)
+	cipher, err := EncryptCTR(key, nounce, []byte(plainText))
+	if err != nil {
+		panic(err)
+	}
 	fmt.Println("cipherText(bytes): ", cipher)
 	fmt.Println("cipherText(string): ", string(cipher))
 
-	plain := aes_ctr_decrypt(cipher, key, nounce)
+	plain, err := DecryptCTR(key, nounce, cipher)
+	if err != nil {
+		panic(err)
+	}
 	fmt.Println("decryptedText(bytes): ", plain)
 	fmt.Println("decryptedText(string): ", string(plain))
 }
 
-func aes_ctr_encrypt(plaintext, key, nonce []byte) []byte {
-	block, err := aes.NewCipher(key)
+// EncryptCTR encrypts plaintext in CTR mode, using nonce as the initial counter block.
+func EncryptCTR(key, nonce, plaintext []byte) ([]byte, error) {
+	block, err := newBlock(key)
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
-	ciphertext := make([]byte, len(plaintext))
-	stream := cipher.NewCTR(block, nonce)
-	stream.XORKeyStream(ciphertext, plaintext)
 
-	return ciphertext
+	return blockcipher.EncryptCTR(block, nonce, plaintext)
 }
 
-func aes_ctr_decrypt(ciphertext, key, nonce []byte) []byte {
-	return aes_ctr_encrypt(ciphertext, key, nonce) // CTR mode is symmetric
+// DecryptCTR decrypts a ciphertext produced by EncryptCTR.
+func DecryptCTR(key, nonce, ciphertext []byte) ([]byte, error) {
+	return EncryptCTR(key, nonce, ciphertext) // CTR mode is symmetric
 }
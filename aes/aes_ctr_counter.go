@@ -0,0 +1,151 @@
+package aes
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"sync"
+)
+
+var (
+	// ErrCounterOverflow is returned when encrypting would wrap the CTR block counter
+	// and reuse keystream.
+	ErrCounterOverflow = errors.New("aes: CTR counter would wrap")
+
+	// ErrNonceReused is returned by a NonceGuard that has already seen a key/nonce pair.
+	ErrNonceReused = errors.New("aes: nonce reused with the same key")
+
+	// ErrCounterSize is returned for a counter size outside 1 to 16 bytes.
+	ErrCounterSize = errors.New("aes: invalid CTR counter size")
+)
+
+// CTR is AES counter mode with an explicit split of the counter block into a fixed
+// nonce and a big-endian block counter that starts at zero. Unlike cipher.NewCTR it
+// refuses to wrap the counter, so a 32-bit counter limits one nonce to 64 GiB.
+type CTR struct {
+	block     cipher.Block
+	counter   [aes.BlockSize]byte
+	size      int    // counter size in bytes
+	remaining uint64 // blocks left before the counter wraps, when size < 8
+	keystream [aes.BlockSize]byte
+	used      int // bytes of keystream already consumed
+	keySize   KeySize
+}
+
+// NewCTR returns a CTR stream whose counter block is nonce || counter, with a
+// counterSize-byte counter and a (16 - counterSize)-byte nonce. Use counterSize 4 for
+// the 96/32 split of GCM and 8 for a 64/64 split.
+func NewCTR(key, nonce []byte, counterSize int) (*CTR, error) {
+	block, err := newBlock(key)
+	if err != nil {
+		return nil, err
+	}
+	if counterSize < 1 || counterSize > aes.BlockSize {
+		return nil, ErrCounterSize
+	}
+	if err := checkIV(nonce, aes.BlockSize-counterSize); err != nil {
+		return nil, err
+	}
+
+	c := &CTR{block: block, size: counterSize, used: aes.BlockSize, keySize: KeySize(len(key))}
+	copy(c.counter[:], nonce)
+	if counterSize < 8 {
+		c.remaining = 1 << (8 * counterSize)
+	}
+	return c, nil
+}
+
+// KeySize reports the AES variant of the key c was created with.
+func (c *CTR) KeySize() KeySize { return c.keySize }
+
+// XORKeyStream XORs src with the keystream into dst. If the counter cannot cover all
+// of src it returns ErrCounterOverflow without writing anything.
+func (c *CTR) XORKeyStream(dst, src []byte) error {
+	if len(dst) < len(src) {
+		return errors.New("aes: CTR output smaller than input")
+	}
+	if c.size < 8 {
+		buffered := uint64(aes.BlockSize - c.used)
+		if uint64(len(src)) > buffered {
+			needed := (uint64(len(src)) - buffered + aes.BlockSize - 1) / aes.BlockSize
+			if needed > c.remaining {
+				return ErrCounterOverflow
+			}
+		}
+	}
+
+	for i := range src {
+		if c.used == aes.BlockSize {
+			c.refill()
+		}
+		dst[i] = src[i] ^ c.keystream[c.used]
+		c.used++
+	}
+
+	return nil
+}
+
+// refill encrypts the current counter block and increments the counter field.
+func (c *CTR) refill() {
+	c.block.Encrypt(c.keystream[:], c.counter[:])
+	c.used = 0
+	if c.size < 8 {
+		c.remaining--
+	}
+
+	field := c.counter[aes.BlockSize-c.size:]
+	for i := len(field) - 1; i >= 0; i-- {
+		field[i]++
+		if field[i] != 0 {
+			break
+		}
+	}
+}
+
+// NonceGuard remembers every key/nonce pair it has approved within the process and
+// rejects repeats. It stores only SHA-256 digests of the pairs and is safe for
+// concurrent use.
+type NonceGuard struct {
+	mu   sync.Mutex
+	seen map[[sha256.Size]byte]struct{}
+}
+
+// NewNonceGuard returns an empty NonceGuard.
+func NewNonceGuard() *NonceGuard {
+	return &NonceGuard{seen: make(map[[sha256.Size]byte]struct{})}
+}
+
+// Check records the key/nonce pair and returns ErrNonceReused if it was already seen.
+func (g *NonceGuard) Check(key, nonce []byte) error {
+	h := sha256.New()
+	h.Write(binary.BigEndian.AppendUint64(nil, uint64(len(key))))
+	h.Write(key)
+	h.Write(nonce)
+	var digest [sha256.Size]byte
+	h.Sum(digest[:0])
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if _, ok := g.seen[digest]; ok {
+		return ErrNonceReused
+	}
+	g.seen[digest] = struct{}{}
+
+	return nil
+}
+
+// NewCTR is like the package-level NewCTR but first checks the key/nonce pair
+// against the guard.
+func (g *NonceGuard) NewCTR(key, nonce []byte, counterSize int) (*CTR, error) {
+	c, err := NewCTR(key, nonce, counterSize)
+	if err != nil {
+		return nil, err
+	}
+	if err := g.Check(key, nonce); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
@@ -0,0 +1,113 @@
+package aes
+
+import (
+	"bytes"
+	"crypto/cipher"
+	"testing"
+)
+
+func TestCTRCounterOverflow(t *testing.T) {
+	key, nonce := make([]byte, 16), make([]byte, 15)
+
+	// a 1-byte counter covers exactly 256 blocks, written here in uneven pieces
+	c, err := NewCTR(key, nonce, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	total := 0
+	for _, n := range []int{1, 15, 16, 100, 256*16 - 132} {
+		if err := c.XORKeyStream(make([]byte, n), make([]byte, n)); err != nil {
+			t.Fatalf("after %d bytes: %v", total, err)
+		}
+		total += n
+	}
+
+	dst := bytes.Repeat([]byte{0xaa}, 16)
+	if err := c.XORKeyStream(dst, make([]byte, 1)); err != ErrCounterOverflow {
+		t.Errorf("byte 4097: got %v, want ErrCounterOverflow", err)
+	}
+	if !bytes.Equal(dst, bytes.Repeat([]byte{0xaa}, 16)) {
+		t.Error("an overflowing call wrote to dst")
+	}
+
+	// a call that would cross the limit writes nothing, even the part that fits
+	c, _ = NewCTR(key, nonce, 1)
+	dst = make([]byte, 256*16+1)
+	if err := c.XORKeyStream(dst, make([]byte, len(dst))); err != ErrCounterOverflow {
+		t.Errorf("4097 bytes at once: got %v, want ErrCounterOverflow", err)
+	}
+	if !bytes.Equal(dst, make([]byte, len(dst))) {
+		t.Error("an overflowing call wrote to dst")
+	}
+	if err := c.XORKeyStream(dst[:256*16], make([]byte, 256*16)); err != nil {
+		t.Errorf("4096 bytes after the refused call: %v", err)
+	}
+}
+
+// TestCTRMatchesStdlib checks that the 96/32 split with a zero counter produces the
+// same keystream as cipher.NewCTR started at nonce || 0.
+func TestCTRMatchesStdlib(t *testing.T) {
+	key := []byte("0123456789abcdef")
+	nonce := []byte("twelve bytes")
+	plaintext := bytes.Repeat([]byte("counter mode "), 40)
+
+	block, err := newBlock(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := make([]byte, len(plaintext))
+	cipher.NewCTR(block, append(bytes.Clone(nonce), 0, 0, 0, 0)).XORKeyStream(want, plaintext)
+
+	c, err := NewCTR(key, nonce, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := make([]byte, len(plaintext))
+	if err := c.XORKeyStream(got[:7], plaintext[:7]); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.XORKeyStream(got[7:], plaintext[7:]); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("got %x, want %x", got, want)
+	}
+
+	for _, size := range []int{0, 17} {
+		if _, err := NewCTR(key, nonce, size); err != ErrCounterSize {
+			t.Errorf("counter size %d: got %v, want ErrCounterSize", size, err)
+		}
+	}
+	if _, err := NewCTR(key, nonce, 8); err != IVSizeError(12) {
+		t.Errorf("12-byte nonce with an 8-byte counter: got %v, want IVSizeError(12)", err)
+	}
+}
+
+func TestNonceGuard(t *testing.T) {
+	g := NewNonceGuard()
+	key1, key2 := make([]byte, 16), bytes.Repeat([]byte{1}, 16)
+	nonce := make([]byte, 12)
+
+	if _, err := g.NewCTR(key1, nonce, 4); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := g.NewCTR(key1, nonce, 4); err != ErrNonceReused {
+		t.Errorf("repeated key/nonce: got %v, want ErrNonceReused", err)
+	}
+	if _, err := g.NewCTR(key2, nonce, 4); err != nil {
+		t.Errorf("same nonce under another key: %v", err)
+	}
+	other := bytes.Clone(nonce)
+	other[11] = 1
+	if _, err := g.NewCTR(key1, other, 4); err != nil {
+		t.Errorf("another nonce under the same key: %v", err)
+	}
+
+	// the key length is hashed in, so key || nonce boundaries cannot be shifted
+	if err := g.Check([]byte("ab"), []byte("c")); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.Check([]byte("a"), []byte("bc")); err != nil {
+		t.Errorf("shifted key/nonce boundary: %v", err)
+	}
+}
@@ -3,49 +3,83 @@ package aes
 import (
 	"crypto/aes"
 	"fmt"
+
+	"crypt/blockcipher"
 )
 
 func AES_ECB() {
-	key := generateAESKey(32)
+	keySize := AES256
+	key, err := NewKey(keySize)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println("key size: ", keySize)
 	fmt.Println("key(bytes): ", key)
 	fmt.Println("key(string): ", string(key))
 
-	plainText := "Hello this is mustafa!!!! and I am testing aes encryption using ecb mode \nHello this is mustafa!!! and I am testing aes encryption using ecb mode \nI repeated same sentence twice just to see the similarity of ciphertext"
-	cipher := aes_ecb_encrypt([]byte(plainText), key)
+	// the sentence is 80 bytes, five AES blocks, so its second copy starts on a block
+	// boundary and encrypts to the same five ciphertext blocks
+	sentence := "Hello this is mustafa!!!! and I am testing aes encryption using the ecb mode!!!\n"
+	plainText := sentence + sentence + "I repeated same sentence twice just to see the similarity of ciphertext"
+	cipher, err := EncryptECB(key, []byte(plainText))
+	if err != nil {
+		panic(err)
+	}
 	fmt.Println("cipherText(bytes): ", cipher)
 	fmt.Println("cipherText(string): ", string(cipher))
+	fmt.Println("repeated cipherText blocks: ", RepeatedBlocks(cipher, aes.BlockSize))
 
-	plain := aes_ecb_decrypt(cipher, key)
+	plain, err := DecryptECB(key, cipher)
+	if err != nil {
+		panic(err)
+	}
 	fmt.Println("decryptedText(bytes): ", plain)
 	fmt.Println("decryptedText(string): ", string(plain))
 }
 
-func aes_ecb_encrypt(data []byte, key []byte) []byte {
-	blck, err := aes.NewCipher(key)
+// EncryptECB pads data with PKCS#7 and encrypts every block independently.
+// ECB leaks repeated plaintext blocks and is only kept for demonstration.
+func EncryptECB(key, data []byte) ([]byte, error) {
+	return EncryptECBWithPadding(key, data, PKCS7)
+}
+
+// DecryptECB decrypts a ciphertext produced by EncryptECB and removes the padding.
+func DecryptECB(key, cipherText []byte) ([]byte, error) {
+	return DecryptECBWithPadding(key, cipherText, PKCS7)
+}
+
+// EncryptECBWithPadding pads data with the given scheme and encrypts every block independently.
+func EncryptECBWithPadding(key, data []byte, padding Padding) ([]byte, error) {
+	blck, err := newBlock(key)
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
 
-	paddedData := pkcs7Pad(data, aes.BlockSize)
-	cipherText := make([]byte, len(paddedData))
-	for i := 0; i < len(data); i += aes.BlockSize {
-		blck.Encrypt(cipherText[i:i+aes.BlockSize], data[i:i+aes.BlockSize])
-	}
-	return cipherText
+	return blockcipher.EncryptECB(blck, data, padding), nil
 }
 
-func aes_ecb_decrypt(cipherText []byte, key []byte) []byte {
-	blck, err := aes.NewCipher(key)
+// DecryptECBWithPadding decrypts an ECB ciphertext and removes the given padding scheme.
+func DecryptECBWithPadding(key, cipherText []byte, padding Padding) ([]byte, error) {
+	blck, err := newBlock(key)
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
 
-	plainText := make([]byte, len(cipherText))
-	for i := 0; i < len(cipherText); i += aes.BlockSize {
-		blck.Decrypt(plainText[i:i+aes.BlockSize], cipherText[i:i+aes.BlockSize])
-	}
+	return blockcipher.DecryptECB(blck, cipherText, padding)
+}
 
-	unPaddedData := pkcs7Unpad(plainText, aes.BlockSize)
+// RepeatedBlocks counts the blocks of data that are identical to an earlier block.
+// Any non-zero result on ciphertext shows the pattern leak that makes ECB unsafe.
+func RepeatedBlocks(data []byte, blockSize int) int {
+	seen := make(map[string]bool)
+	repeated := 0
+	for i := 0; i+blockSize <= len(data); i += blockSize {
+		block := string(data[i : i+blockSize])
+		if seen[block] {
+			repeated++
+		}
+		seen[block] = true
+	}
 
-	return unPaddedData
+	return repeated
 }
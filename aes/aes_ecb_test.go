@@ -0,0 +1,41 @@
+package aes
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestECBLeaksRepeatedBlocks(t *testing.T) {
+	key := make([]byte, 32)
+	block := []byte("sixteen byte blk")
+
+	cases := []struct {
+		plaintext []byte
+		repeated  int
+	}{
+		// four identical aligned blocks leak three repeats
+		{bytes.Repeat(block, 4), 3},
+		// one byte between the copies moves them off the block boundary
+		{bytes.Join([][]byte{block, block, block, block}, []byte("x")), 0},
+		{[]byte("no block of this plaintext repeats"), 0},
+	}
+
+	for _, c := range cases {
+		ciphertext, err := EncryptECB(key, c.plaintext)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := RepeatedBlocks(ciphertext, 16); got != c.repeated {
+			t.Errorf("%q: RepeatedBlocks = %d, want %d", c.plaintext, got, c.repeated)
+		}
+	}
+
+	// CBC chains the blocks, so the same plaintext shows no repeats
+	ciphertext, err := EncryptCBC(key, make([]byte, 16), bytes.Repeat(block, 4))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := RepeatedBlocks(ciphertext, 16); got != 0 {
+		t.Errorf("CBC: RepeatedBlocks = %d, want 0", got)
+	}
+}
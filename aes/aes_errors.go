@@ -0,0 +1,26 @@
+package aes
+
+import (
+	"errors"
+	"strconv"
+
+	"crypt/blockcipher"
+)
+
+// KeySizeError is returned when a key is not 16, 24 or 32 bytes long.
+type KeySizeError int
+
+func (k KeySizeError) Error() string {
+	return "aes: invalid key size " + strconv.Itoa(int(k))
+}
+
+// IVSizeError is returned when an IV or nonce does not have the length the mode expects.
+type IVSizeError = blockcipher.IVSizeError
+
+var (
+	// ErrInvalidCiphertext is returned when a ciphertext is empty or is not a multiple of the block size.
+	ErrInvalidCiphertext = blockcipher.ErrInvalidCiphertext
+
+	// ErrAuthenticationFailed is returned when an authenticated mode rejects a ciphertext.
+	ErrAuthenticationFailed = errors.New("aes: message authentication failed")
+)
@@ -0,0 +1,147 @@
+package aes
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"io"
+
+	"golang.org/x/crypto/hkdf"
+)
+
+const etmTagSize = sha256.Size
+
+type etmMode int
+
+const (
+	etmCBC etmMode = iota
+	etmCTR
+)
+
+func (m etmMode) String() string {
+	if m == etmCBC {
+		return "cbc"
+	}
+	return "ctr"
+}
+
+// encryptThenMAC is a cipher.AEAD that encrypts with AES-CBC or AES-CTR and then
+// authenticates IV || ciphertext || AAD || len(AAD) with HMAC-SHA-256.
+type encryptThenMAC struct {
+	block  cipher.Block
+	macKey []byte
+	mode   etmMode
+	size   KeySize
+}
+
+// NewCBCHMAC returns a cipher.AEAD that combines AES-CBC with PKCS#7 padding and
+// HMAC-SHA-256 in encrypt-then-MAC order. The nonce is the 16-byte CBC IV and must
+// be unpredictable. Separate encryption and MAC keys are derived from key with HKDF.
+func NewCBCHMAC(key []byte) (cipher.AEAD, error) {
+	return newEncryptThenMAC(key, etmCBC)
+}
+
+// NewCTRHMAC returns a cipher.AEAD that combines AES-CTR with HMAC-SHA-256 in
+// encrypt-then-MAC order. The nonce is the 16-byte initial counter block and must
+// never repeat under the same key.
+func NewCTRHMAC(key []byte) (cipher.AEAD, error) {
+	return newEncryptThenMAC(key, etmCTR)
+}
+
+func newEncryptThenMAC(key []byte, mode etmMode) (cipher.AEAD, error) {
+	if _, err := newBlock(key); err != nil {
+		return nil, err
+	}
+
+	info := "crypt aes-" + mode.String() + "-hmac-sha256 "
+	kdf := hkdf.New(sha256.New, key, nil, []byte(info+"encryption"))
+	encKey := make([]byte, len(key))
+	if _, err := io.ReadFull(kdf, encKey); err != nil {
+		return nil, err
+	}
+	kdf = hkdf.New(sha256.New, key, nil, []byte(info+"authentication"))
+	macKey := make([]byte, etmTagSize)
+	if _, err := io.ReadFull(kdf, macKey); err != nil {
+		return nil, err
+	}
+
+	block, err := newBlock(encKey)
+	if err != nil {
+		return nil, err
+	}
+	return &encryptThenMAC{block: block, macKey: macKey, mode: mode, size: KeySize(len(key))}, nil
+}
+
+func (e *encryptThenMAC) KeySize() KeySize { return e.size }
+
+func (e *encryptThenMAC) NonceSize() int { return aes.BlockSize }
+
+func (e *encryptThenMAC) Overhead() int {
+	if e.mode == etmCBC {
+		return aes.BlockSize + etmTagSize // worst case PKCS#7 padding plus tag
+	}
+	return etmTagSize
+}
+
+func (e *encryptThenMAC) Seal(dst, nonce, plaintext, additionalData []byte) []byte {
+	if len(nonce) != aes.BlockSize {
+		panic("aes: incorrect nonce length given to encrypt-then-MAC")
+	}
+
+	var ciphertext []byte
+	if e.mode == etmCBC {
+		data := PKCS7.Pad(plaintext, aes.BlockSize)
+		ciphertext = make([]byte, len(data))
+		cipher.NewCBCEncrypter(e.block, nonce).CryptBlocks(ciphertext, data)
+	} else {
+		ciphertext = make([]byte, len(plaintext))
+		cipher.NewCTR(e.block, nonce).XORKeyStream(ciphertext, plaintext)
+	}
+
+	dst = append(dst, ciphertext...)
+	return append(dst, e.tag(nonce, ciphertext, additionalData)...)
+}
+
+func (e *encryptThenMAC) Open(dst, nonce, ciphertext, additionalData []byte) ([]byte, error) {
+	if len(nonce) != aes.BlockSize {
+		panic("aes: incorrect nonce length given to encrypt-then-MAC")
+	}
+	if len(ciphertext) < etmTagSize {
+		return nil, ErrAuthenticationFailed
+	}
+
+	body := ciphertext[:len(ciphertext)-etmTagSize]
+	tag := ciphertext[len(ciphertext)-etmTagSize:]
+	if !hmac.Equal(tag, e.tag(nonce, body, additionalData)) {
+		return nil, ErrAuthenticationFailed
+	}
+
+	if e.mode == etmCTR {
+		plaintext := make([]byte, len(body))
+		cipher.NewCTR(e.block, nonce).XORKeyStream(plaintext, body)
+		return append(dst, plaintext...), nil
+	}
+
+	if err := checkBlocks(body, aes.BlockSize); err != nil {
+		return nil, err
+	}
+	plaintext := make([]byte, len(body))
+	cipher.NewCBCDecrypter(e.block, nonce).CryptBlocks(plaintext, body)
+	plaintext, err := PKCS7.Unpad(plaintext, aes.BlockSize)
+	if err != nil {
+		return nil, err
+	}
+	return append(dst, plaintext...), nil
+}
+
+func (e *encryptThenMAC) tag(nonce, ciphertext, additionalData []byte) []byte {
+	mac := hmac.New(sha256.New, e.macKey)
+	mac.Write(nonce)
+	mac.Write(ciphertext)
+	mac.Write(additionalData)
+	mac.Write(binary.BigEndian.AppendUint64(nil, uint64(len(additionalData))*8))
+
+	return mac.Sum(nil)
+}
@@ -0,0 +1,123 @@
+package aes
+
+import (
+	"bytes"
+	"crypto/cipher"
+	"testing"
+)
+
+func newETM(t *testing.T) map[string]cipher.AEAD {
+	t.Helper()
+	key := []byte("0123456789abcdef0123456789abcdef")
+	cbc, err := NewCBCHMAC(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctr, err := NewCTRHMAC(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return map[string]cipher.AEAD{"CBC": cbc, "CTR": ctr}
+}
+
+func TestEncryptThenMACRoundTrip(t *testing.T) {
+	nonce := []byte("sixteen byte iv!")
+	ad := []byte("associated data")
+	prefix := []byte("prefix")
+
+	for name, aead := range newETM(t) {
+		for _, n := range []int{0, 1, 15, 16, 17, 100} {
+			plaintext := bytes.Repeat([]byte{'p'}, n)
+
+			// dst shares its backing array with a prefix that must survive
+			dst := append(make([]byte, 0, 256), prefix...)
+			sealed := aead.Seal(dst, nonce, plaintext, ad)
+			if !bytes.Equal(sealed[:len(prefix)], prefix) || len(sealed)-len(prefix) > n+aead.Overhead() {
+				t.Fatalf("%s, %d bytes: Seal returned %x", name, n, sealed)
+			}
+
+			opened, err := aead.Open(prefix, nonce, sealed[len(prefix):], ad)
+			if err != nil || !bytes.Equal(opened, append(bytes.Clone(prefix), plaintext...)) {
+				t.Errorf("%s, %d bytes: Open = %q, %v", name, n, opened, err)
+			}
+		}
+	}
+}
+
+func TestEncryptThenMACTampering(t *testing.T) {
+	nonce := []byte("sixteen byte iv!")
+	ad := []byte("associated data")
+	plaintext := []byte("a message of more than one AES block")
+
+	for name, aead := range newETM(t) {
+		sealed := aead.Seal(nil, nonce, plaintext, ad)
+
+		badNonce := bytes.Clone(nonce)
+		badNonce[0] ^= 1
+		if _, err := aead.Open(nil, badNonce, sealed, ad); err != ErrAuthenticationFailed {
+			t.Errorf("%s, flipped IV bit: got %v, want ErrAuthenticationFailed", name, err)
+		}
+		for _, i := range []int{0, len(sealed) - etmTagSize - 1, len(sealed) - etmTagSize, len(sealed) - 1} {
+			tampered := bytes.Clone(sealed)
+			tampered[i] ^= 1
+			if _, err := aead.Open(nil, nonce, tampered, ad); err != ErrAuthenticationFailed {
+				t.Errorf("%s, flipped bit at %d: got %v, want ErrAuthenticationFailed", name, i, err)
+			}
+		}
+		badAD := bytes.Clone(ad)
+		badAD[len(badAD)-1] ^= 1
+		if _, err := aead.Open(nil, nonce, sealed, badAD); err != ErrAuthenticationFailed {
+			t.Errorf("%s, flipped AAD bit: got %v, want ErrAuthenticationFailed", name, err)
+		}
+		// moving bytes between the ciphertext and the AAD changes the AAD length
+		if _, err := aead.Open(nil, nonce, sealed, append(bytes.Clone(ad), 0)); err != ErrAuthenticationFailed {
+			t.Errorf("%s, extended AAD: got %v, want ErrAuthenticationFailed", name, err)
+		}
+		for _, n := range []int{0, 1, etmTagSize - 1} {
+			if _, err := aead.Open(nil, nonce, sealed[:n], ad); err != ErrAuthenticationFailed {
+				t.Errorf("%s, %d bytes: got %v, want ErrAuthenticationFailed", name, n, err)
+			}
+		}
+	}
+}
+
+// TestCBCHMACBadBody covers CBC bodies that carry a valid tag, as they would if the
+// MAC key leaked, but cannot be decrypted.
+func TestCBCHMACBadBody(t *testing.T) {
+	nonce := make([]byte, 16)
+	e := newETM(t)["CBC"].(*encryptThenMAC)
+
+	body := bytes.Repeat([]byte{1}, 15)
+	forged := append(bytes.Clone(body), e.tag(nonce, body, nil)...)
+	if _, err := e.Open(nil, nonce, forged, nil); err != ErrInvalidCiphertext {
+		t.Errorf("15-byte body: got %v, want ErrInvalidCiphertext", err)
+	}
+
+	// a whole block whose plaintext ends in 0 is not valid PKCS#7
+	body = make([]byte, 16)
+	cipher.NewCBCEncrypter(e.block, nonce).CryptBlocks(body, make([]byte, 16))
+	forged = append(bytes.Clone(body), e.tag(nonce, body, nil)...)
+	if _, err := e.Open(nil, nonce, forged, nil); err != ErrInvalidPadding {
+		t.Errorf("bad padding: got %v, want ErrInvalidPadding", err)
+	}
+}
+
+func TestEncryptThenMACKeySeparation(t *testing.T) {
+	aeads := newETM(t)
+	cbc, ctr := aeads["CBC"].(*encryptThenMAC), aeads["CTR"].(*encryptThenMAC)
+
+	if bytes.Equal(cbc.macKey, ctr.macKey) {
+		t.Error("CBC and CTR derive the same MAC key")
+	}
+	a, b := make([]byte, 16), make([]byte, 16)
+	cbc.block.Encrypt(a, make([]byte, 16))
+	ctr.block.Encrypt(b, make([]byte, 16))
+	if bytes.Equal(a, b) {
+		t.Error("CBC and CTR derive the same encryption key")
+	}
+
+	if _, err := NewCBCHMAC(make([]byte, 20)); err != KeySizeError(20) {
+		t.Errorf("20-byte key: got %v, want KeySizeError(20)", err)
+	}
+}
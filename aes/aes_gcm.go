@@ -1,57 +1,98 @@
 package aes
 
 import (
-	"crypto/aes"
 	"crypto/cipher"
 	"fmt"
+
+	"crypt/drbg"
 )
 
 func AES_GCM() {
-	key := generateAESKey(32)
+	keySize := AES256
+	key, err := NewKey(keySize)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println("key size: ", keySize)
 	fmt.Println("key(bytes): ", key)
 	fmt.Println("key(string): ", string(key))
 
-	nounce := generateIV(12) // AES-GCM requires a nonce of at least 12 bytes (recommended by NIST).
+	nounce, err := drbg.Read(nil, 12) // AES-GCM requires a nonce of at least 12 bytes (recommended by NIST).
+	if err != nil {
+		panic(err)
+	}
 	fmt.Println("iv(bytes): ", nounce)
 	fmt.Println("iv(string): ", string(nounce))
 
 	plainText := "Hello this is mustafa!!!! and I am testing aes encryption using ecb mode \nHello this is mustafa!!! and I am testing aes encryption using ecb mode \nI repeated same sentence twice just to see the similarity of ciphertext"
-	cipher := aes_gcm_encrypt([]byte(plainText), key, nounce)
+	cipher, err := EncryptGCM(key, nounce, []byte(plainText))
+	if err != nil {
+		panic(err)
+	}
 	fmt.Println("cipherText(bytes): ", cipher)
 	fmt.Println("cipherText(string): ", string(cipher))
 
-	plain := aes_gcm_decrypt(cipher, key, nounce)
+	plain, err := DecryptGCM(key, nounce, cipher)
+	if err != nil {
+		panic(err)
+	}
 	fmt.Println("decryptedText(bytes): ", plain)
 	fmt.Println("decryptedText(string): ", string(plain))
 }
 
-func aes_gcm_encrypt(plaintext, key, nonce []byte) []byte {
-	block, err := aes.NewCipher(key)
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := newBlock(key)
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
-	aesGCM, err := cipher.NewGCM(block)
+
+	return cipher.NewGCM(block)
+}
+
+// EncryptGCM encrypts and authenticates plaintext with AES-GCM. The nonce must be 12 bytes.
+func EncryptGCM(key, nonce, plaintext []byte) ([]byte, error) {
+	aesGCM, err := newGCM(key)
 	if err != nil {
-		panic(err)
+		return nil, err
+	}
+	if err := checkIV(nonce, aesGCM.NonceSize()); err != nil {
+		return nil, err
 	}
 
 	ciphertext := aesGCM.Seal(nil, nonce, plaintext, nil)
-	return ciphertext
+	return ciphertext, nil
 }
 
-func aes_gcm_decrypt(ciphertext, key, nonce []byte) []byte {
-	block, err := aes.NewCipher(key)
+// DecryptGCM authenticates and decrypts a ciphertext produced by EncryptGCM.
+func DecryptGCM(key, nonce, ciphertext []byte) ([]byte, error) {
+	aesGCM, err := newGCM(key)
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
-	aesGCM, err := cipher.NewGCM(block)
-	if err != nil {
-		panic(err)
+	if err := checkIV(nonce, aesGCM.NonceSize()); err != nil {
+		return nil, err
+	}
+	if len(ciphertext) < aesGCM.Overhead() {
+		return nil, ErrInvalidCiphertext
 	}
 
 	plaintext, err := aesGCM.Open(nil, nonce, ciphertext, nil)
 	if err != nil {
-		panic("Decryption failed!")
+		return nil, ErrAuthenticationFailed
 	}
-	return plaintext
+	return plaintext, nil
+}
+
+// GMAC authenticates message with AES-GCM using empty plaintext and returns the
+// 16-byte tag. The nonce must be 12 bytes and unique per key.
+func GMAC(key, nonce, message []byte) ([]byte, error) {
+	aesGCM, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkIV(nonce, aesGCM.NonceSize()); err != nil {
+		return nil, err
+	}
+
+	return aesGCM.Seal(nil, nonce, nil, message), nil
 }
@@ -0,0 +1,57 @@
+package aes
+
+import (
+	"errors"
+	"io"
+
+	"crypt/drbg"
+)
+
+// gcmEnvelopeVersion is the first byte of every envelope produced by SealGCM.
+const gcmEnvelopeVersion byte = 1
+
+// ErrInvalidEnvelope is returned when an envelope is truncated or has an unknown version byte.
+var ErrInvalidEnvelope = errors.New("aes: invalid GCM envelope")
+
+// SealGCM encrypts plaintext with AES-GCM under a freshly generated nonce and
+// returns a self-describing envelope: version(1) || nonce(12) || ciphertext || tag(16).
+// additionalData is authenticated but not encrypted and may be nil. The nonce is
+// read from rand.
+func SealGCM(rand io.Reader, key, plaintext, additionalData []byte) ([]byte, error) {
+	aesGCM, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce, err := drbg.Read(rand, aesGCM.NonceSize())
+	if err != nil {
+		return nil, err
+	}
+
+	envelope := make([]byte, 0, 1+len(nonce)+len(plaintext)+aesGCM.Overhead())
+	envelope = append(envelope, gcmEnvelopeVersion)
+	envelope = append(envelope, nonce...)
+
+	return aesGCM.Seal(envelope, nonce, plaintext, additionalData), nil
+}
+
+// OpenGCM parses an envelope produced by SealGCM, authenticates it together with
+// additionalData and returns the plaintext.
+func OpenGCM(key, envelope, additionalData []byte) ([]byte, error) {
+	aesGCM, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(envelope) < 1+aesGCM.NonceSize()+aesGCM.Overhead() || envelope[0] != gcmEnvelopeVersion {
+		return nil, ErrInvalidEnvelope
+	}
+	nonce := envelope[1 : 1+aesGCM.NonceSize()]
+	ciphertext := envelope[1+aesGCM.NonceSize():]
+
+	plaintext, err := aesGCM.Open(nil, nonce, ciphertext, additionalData)
+	if err != nil {
+		return nil, ErrAuthenticationFailed
+	}
+	return plaintext, nil
+}
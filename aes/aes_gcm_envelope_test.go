@@ -0,0 +1,62 @@
+package aes
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestGCMEnvelope(t *testing.T) {
+	key := make([]byte, 32)
+	plaintext := []byte("envelope plaintext")
+	ad := []byte("header")
+
+	envelope, err := SealGCM(nil, key, plaintext, ad)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(envelope) != 1+12+len(plaintext)+16 || envelope[0] != gcmEnvelopeVersion {
+		t.Fatalf("envelope has %d bytes and version %d", len(envelope), envelope[0])
+	}
+	if got, err := OpenGCM(key, envelope, ad); err != nil || !bytes.Equal(got, plaintext) {
+		t.Fatalf("OpenGCM = %q, %v", got, err)
+	}
+
+	// an empty plaintext still carries version, nonce and tag
+	empty, err := SealGCM(nil, key, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := OpenGCM(key, empty, nil); err != nil || len(got) != 0 {
+		t.Errorf("empty plaintext: OpenGCM = %q, %v", got, err)
+	}
+
+	wrongVersion := bytes.Clone(envelope)
+	wrongVersion[0] = gcmEnvelopeVersion + 1
+	if _, err := OpenGCM(key, wrongVersion, ad); err != ErrInvalidEnvelope {
+		t.Errorf("version %d: got %v, want ErrInvalidEnvelope", wrongVersion[0], err)
+	}
+	for _, n := range []int{0, 1, 13, 1 + 12 + 16 - 1} {
+		if _, err := OpenGCM(key, envelope[:n], ad); err != ErrInvalidEnvelope {
+			t.Errorf("%d bytes: got %v, want ErrInvalidEnvelope", n, err)
+		}
+	}
+
+	tampered := map[string]int{
+		"nonce":      1,
+		"ciphertext": 1 + 12,
+		"tag":        len(envelope) - 1,
+	}
+	for name, i := range tampered {
+		env := bytes.Clone(envelope)
+		env[i] ^= 1
+		if _, err := OpenGCM(key, env, ad); err != ErrAuthenticationFailed {
+			t.Errorf("flipped %s byte: got %v, want ErrAuthenticationFailed", name, err)
+		}
+	}
+	if _, err := OpenGCM(key, envelope, []byte("other header")); err != ErrAuthenticationFailed {
+		t.Errorf("mismatched AAD: got %v, want ErrAuthenticationFailed", err)
+	}
+	if _, err := OpenGCM(key, envelope, nil); err != ErrAuthenticationFailed {
+		t.Errorf("missing AAD: got %v, want ErrAuthenticationFailed", err)
+	}
+}
@@ -0,0 +1,237 @@
+package aes
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/subtle"
+	"encoding/binary"
+)
+
+const (
+	gcmSIVNonceSize = 12
+	gcmSIVTagSize   = 16
+	gcmSIVMaxLength = 1 << 36 // RFC 8452 limit for plaintext and associated data
+)
+
+// gcmSIV implements AEAD_AES_128_GCM_SIV and AEAD_AES_256_GCM_SIV (RFC 8452).
+type gcmSIV struct {
+	block  cipher.Block // key-generating key
+	keyLen int
+}
+
+// NewGCMSIV returns a cipher.AEAD implementing AES-GCM-SIV with a 16- or 32-byte key.
+// Reusing a nonce only reveals whether two messages are identical, instead of
+// exposing the authentication key as it does with plain GCM.
+func NewGCMSIV(key []byte) (cipher.AEAD, error) {
+	if len(key) != 16 && len(key) != 32 {
+		return nil, KeySizeError(len(key))
+	}
+
+	block, err := newBlock(key)
+	if err != nil {
+		return nil, err
+	}
+	return &gcmSIV{block: block, keyLen: len(key)}, nil
+}
+
+func (g *gcmSIV) KeySize() KeySize { return KeySize(g.keyLen) }
+
+func (g *gcmSIV) NonceSize() int { return gcmSIVNonceSize }
+
+func (g *gcmSIV) Overhead() int { return gcmSIVTagSize }
+
+func (g *gcmSIV) Seal(dst, nonce, plaintext, additionalData []byte) []byte {
+	if len(nonce) != gcmSIVNonceSize {
+		panic("aes: incorrect nonce length given to GCM-SIV")
+	}
+	if uint64(len(plaintext)) > gcmSIVMaxLength || uint64(len(additionalData)) > gcmSIVMaxLength {
+		panic("aes: message too large for GCM-SIV")
+	}
+
+	authKey, encBlock := g.deriveKeys(nonce)
+	tag := g.tag(authKey, encBlock, nonce, plaintext, additionalData)
+
+	ciphertext := make([]byte, len(plaintext))
+	gcmSIVCTR(encBlock, tag, ciphertext, plaintext)
+
+	dst = append(dst, ciphertext...)
+	return append(dst, tag...)
+}
+
+func (g *gcmSIV) Open(dst, nonce, ciphertext, additionalData []byte) ([]byte, error) {
+	if len(nonce) != gcmSIVNonceSize {
+		panic("aes: incorrect nonce length given to GCM-SIV")
+	}
+	if len(ciphertext) < gcmSIVTagSize || uint64(len(ciphertext)) > gcmSIVMaxLength+gcmSIVTagSize ||
+		uint64(len(additionalData)) > gcmSIVMaxLength {
+		return nil, ErrAuthenticationFailed
+	}
+
+	tag := ciphertext[len(ciphertext)-gcmSIVTagSize:]
+	ciphertext = ciphertext[:len(ciphertext)-gcmSIVTagSize]
+
+	authKey, encBlock := g.deriveKeys(nonce)
+	plaintext := make([]byte, len(ciphertext))
+	gcmSIVCTR(encBlock, tag, plaintext, ciphertext)
+
+	if subtle.ConstantTimeCompare(tag, g.tag(authKey, encBlock, nonce, plaintext, additionalData)) != 1 {
+		return nil, ErrAuthenticationFailed
+	}
+	return append(dst, plaintext...), nil
+}
+
+// deriveKeys derives the per-nonce POLYVAL key and encryption block cipher (RFC 8452 section 4).
+func (g *gcmSIV) deriveKeys(nonce []byte) ([]byte, cipher.Block) {
+	var in, out [aes.BlockSize]byte
+	copy(in[4:], nonce)
+
+	derived := make([]byte, 0, 16+g.keyLen)
+	for i := uint32(0); len(derived) < cap(derived); i++ {
+		binary.LittleEndian.PutUint32(in[:4], i)
+		g.block.Encrypt(out[:], in[:])
+		derived = append(derived, out[:8]...)
+	}
+
+	encBlock, err := aes.NewCipher(derived[16:])
+	if err != nil {
+		panic(err) // the derived key always has the length of the main key
+	}
+	return derived[:16], encBlock
+}
+
+func (g *gcmSIV) tag(authKey []byte, encBlock cipher.Block, nonce, plaintext, additionalData []byte) []byte {
+	p := newPolyval(authKey)
+	p.update(additionalData)
+	p.update(plaintext)
+
+	var lengths [aes.BlockSize]byte
+	binary.LittleEndian.PutUint64(lengths[:8], uint64(len(additionalData))*8)
+	binary.LittleEndian.PutUint64(lengths[8:], uint64(len(plaintext))*8)
+	p.update(lengths[:])
+
+	s := p.sum()
+	subtle.XORBytes(s[:gcmSIVNonceSize], s[:gcmSIVNonceSize], nonce)
+	s[15] &= 0x7f
+
+	tag := make([]byte, gcmSIVTagSize)
+	encBlock.Encrypt(tag, s)
+	return tag
+}
+
+// gcmSIVCTR is the counter mode of RFC 8452: the initial block is the tag with its
+// top bit set and only the first 32 bits, read little-endian, are incremented.
+func gcmSIVCTR(block cipher.Block, tag, dst, src []byte) {
+	var counter, keystream [aes.BlockSize]byte
+	copy(counter[:], tag)
+	counter[15] |= 0x80
+
+	for len(src) > 0 {
+		block.Encrypt(keystream[:], counter[:])
+		n := subtle.XORBytes(dst, src, keystream[:])
+		dst, src = dst[n:], src[n:]
+
+		binary.LittleEndian.PutUint32(counter[:4], binary.LittleEndian.Uint32(counter[:4])+1)
+	}
+}
+
+// polyval computes POLYVAL (RFC 8452 section 3) using the GHASH representation,
+// following the mapping in appendix A: POLYVAL(H, X) = rev(GHASH(mulX(rev(H)), rev(X))).
+type polyval struct {
+	hHi, hLo uint64
+	sHi, sLo uint64
+}
+
+func newPolyval(key []byte) *polyval {
+	var h [aes.BlockSize]byte
+	reverseBlock(h[:], key)
+
+	hi, lo := binary.BigEndian.Uint64(h[:8]), binary.BigEndian.Uint64(h[8:])
+	hi, lo = ghashMulX(hi, lo)
+	return &polyval{hHi: hi, hLo: lo}
+}
+
+// update absorbs data zero-padded to a whole number of blocks.
+func (p *polyval) update(data []byte) {
+	var block, rev [aes.BlockSize]byte
+	for len(data) > 0 {
+		block = [aes.BlockSize]byte{}
+		n := copy(block[:], data)
+		data = data[n:]
+
+		reverseBlock(rev[:], block[:])
+		p.sHi ^= binary.BigEndian.Uint64(rev[:8])
+		p.sLo ^= binary.BigEndian.Uint64(rev[8:])
+		p.sHi, p.sLo = ghashMul(p.sHi, p.sLo, p.hHi, p.hLo)
+	}
+}
+
+func (p *polyval) sum() []byte {
+	var s [aes.BlockSize]byte
+	binary.BigEndian.PutUint64(s[:8], p.sHi)
+	binary.BigEndian.PutUint64(s[8:], p.sLo)
+
+	out := make([]byte, aes.BlockSize)
+	reverseBlock(out, s[:])
+	return out
+}
+
+func reverseBlock(dst, src []byte) {
+	for i := range src {
+		dst[len(src)-1-i] = src[i]
+	}
+}
+
+// ghashMulX multiplies by x in the bit-reflected GHASH field.
+func ghashMulX(hi, lo uint64) (uint64, uint64) {
+	mask := -(lo & 1)
+	lo = lo>>1 | hi<<63
+	hi = hi>>1 ^ 0xe100000000000000&mask
+
+	return hi, lo
+}
+
+// ghashMul is the constant-time shift-and-add multiplication of NIST SP 800-38D algorithm 1.
+func ghashMul(xHi, xLo, yHi, yLo uint64) (uint64, uint64) {
+	var zHi, zLo uint64
+	vHi, vLo := yHi, yLo
+	for i := 0; i < 128; i++ {
+		var bit uint64
+		if i < 64 {
+			bit = xHi >> (63 - i) & 1
+		} else {
+			bit = xLo >> (127 - i) & 1
+		}
+		mask := -bit
+		zHi ^= vHi & mask
+		zLo ^= vLo & mask
+		vHi, vLo = ghashMulX(vHi, vLo)
+	}
+
+	return zHi, zLo
+}
+
+// EncryptGCMSIV encrypts and authenticates plaintext with AES-GCM-SIV. The nonce must be 12 bytes.
+func EncryptGCMSIV(key, nonce, plaintext []byte) ([]byte, error) {
+	aesGCMSIV, err := NewGCMSIV(key)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkIV(nonce, gcmSIVNonceSize); err != nil {
+		return nil, err
+	}
+
+	return aesGCMSIV.Seal(nil, nonce, plaintext, nil), nil
+}
+
+// DecryptGCMSIV authenticates and decrypts a ciphertext produced by EncryptGCMSIV.
+func DecryptGCMSIV(key, nonce, ciphertext []byte) ([]byte, error) {
+	aesGCMSIV, err := NewGCMSIV(key)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkIV(nonce, gcmSIVNonceSize); err != nil {
+		return nil, err
+	}
+
+	return aesGCMSIV.Open(nil, nonce, ciphertext, nil)
+}
@@ -0,0 +1,40 @@
+package aes
+
+import (
+	"io"
+
+	"crypt/aeadstream"
+)
+
+// GCMChunkSize is the amount of plaintext sealed into each chunk of a GCM stream.
+const GCMChunkSize = aeadstream.SegmentSize
+
+// ErrInvalidStream is returned when a GCM stream header is missing or malformed,
+// or when a stream would need more chunks than the counter can address.
+var ErrInvalidStream = aeadstream.ErrInvalidStream
+
+// NewGCMEncryptingWriter returns a writer that splits its input into GCMChunkSize
+// chunks and seals each one with AES-GCM, using the STREAM framing of the
+// aeadstream package. Close must be called to write the final chunk; it also
+// closes w if w is an io.Closer. The nonce prefix is read from rand.
+func NewGCMEncryptingWriter(rand io.Reader, key []byte, w io.Writer) (io.WriteCloser, error) {
+	aesGCM, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return aeadstream.NewEncrypter(rand, aesGCM, w)
+}
+
+// NewGCMDecryptingReader returns a reader that authenticates and decrypts a stream
+// produced by NewGCMEncryptingWriter. No plaintext from a chunk is returned before
+// that chunk has been authenticated, and a dropped, reordered or appended chunk is
+// reported as ErrAuthenticationFailed, as OpenGCM does.
+func NewGCMDecryptingReader(key []byte, r io.Reader) (io.Reader, error) {
+	aesGCM, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return aeadstream.NewDecrypterWithError(aesGCM, r, ErrAuthenticationFailed)
+}
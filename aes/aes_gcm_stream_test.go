@@ -0,0 +1,66 @@
+package aes
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"errors"
+	"io"
+	"testing"
+
+	"crypt/aeadstream"
+)
+
+// TestGCMStreamIsAESGCM checks that the stream is plain aeadstream framing over
+// AES-GCM, so any AES-GCM implementation can read it.
+func TestGCMStreamIsAESGCM(t *testing.T) {
+	key := bytes.Repeat([]byte{9}, 24)
+	plaintext := bytes.Repeat([]byte("chunk"), GCMChunkSize/2)
+
+	var buf bytes.Buffer
+	w, err := NewGCMEncryptingWriter(nil, key, &buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.Write(plaintext)
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	block, _ := aes.NewCipher(key)
+	gcm, _ := cipher.NewGCM(block)
+	r, err := aeadstream.NewDecrypter(gcm, &buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := io.ReadAll(r); err != nil || !bytes.Equal(got, plaintext) {
+		t.Errorf("crypto/aes GCM could not read the stream: %v", err)
+	}
+}
+
+func TestGCMStreamErrors(t *testing.T) {
+	key := make([]byte, 32)
+	var buf bytes.Buffer
+	w, err := NewGCMEncryptingWriter(nil, key, &buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.Write([]byte("authenticated"))
+	w.Close()
+
+	r, err := NewGCMDecryptingReader(key, bytes.NewReader(buf.Bytes()[:buf.Len()-1]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = io.ReadAll(r)
+	if !errors.Is(err, ErrAuthenticationFailed) || !errors.Is(err, aeadstream.ErrAuthenticationFailed) {
+		t.Errorf("truncated stream: got %v, want ErrAuthenticationFailed", err)
+	}
+
+	if _, err := NewGCMDecryptingReader(key, bytes.NewReader(buf.Bytes()[:3])); err != ErrInvalidStream {
+		t.Errorf("short header: got %v, want ErrInvalidStream", err)
+	}
+	if _, err := NewGCMEncryptingWriter(nil, key[:20], io.Discard); err != KeySizeError(20) {
+		t.Errorf("20-byte key: got %v, want KeySizeError(20)", err)
+	}
+}
@@ -0,0 +1,39 @@
+package aes
+
+import (
+	"bytes"
+	"testing"
+)
+
+// TestGMACVectors are GCM test cases with empty plaintext: test case 1 of the GCM
+// specification and a 128-bit AAD vector from the NIST GCM validation suite.
+func TestGMACVectors(t *testing.T) {
+	cases := []struct {
+		key, nonce, message, tag string
+	}{
+		{
+			"00000000000000000000000000000000",
+			"000000000000000000000000",
+			"",
+			"58e2fccefa7e3061367f1d57a4e7455a",
+		},
+		{
+			"77be63708971c4e240d1cb79e8d77feb",
+			"e0e00f19fed7ba0136a797f3",
+			"7a43ec1d9c0a5a78a0b16533a6213cab",
+			"209fcc8d3675ed938e9c7166709dd946",
+		},
+	}
+
+	for i, c := range cases {
+		want := decodeHex(t, c.tag)
+		got, err := GMAC(decodeHex(t, c.key), decodeHex(t, c.nonce), decodeHex(t, c.message))
+		if err != nil || !bytes.Equal(got, want) {
+			t.Errorf("case %d: got %x, %v, want %x", i+1, got, err, want)
+		}
+	}
+
+	if _, err := GMAC(make([]byte, 16), make([]byte, 8), nil); err != IVSizeError(8) {
+		t.Errorf("8-byte nonce: got %v, want IVSizeError(8)", err)
+	}
+}
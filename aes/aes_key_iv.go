@@ -1,43 +1,31 @@
 package aes
 
 import (
-	"bytes"
-	"crypto/rand"
-	"io"
+	"crypto/aes"
+	"crypto/cipher"
 )
 
-func generateAESKey(size int) []byte {
-	key := make([]byte, size)
-
-	_, err := io.ReadFull(rand.Reader, key)
-	if err != nil {
-		panic(err)
+func newBlock(key []byte) (cipher.Block, error) {
+	if _, err := KeySizeOf(key); err != nil {
+		return nil, err
 	}
 
-	return key
+	return aes.NewCipher(key)
 }
 
-func generateIV(size int) []byte {
-	iv := make([]byte, size)
-
-	_, err := io.ReadFull(rand.Reader, iv)
-	if err != nil {
-		panic(err)
+func checkIV(iv []byte, size int) error {
+	if len(iv) != size {
+		return IVSizeError(len(iv))
 	}
 
-	return iv
-}
-
-func pkcs7Pad(data []byte, blockSize int) []byte {
-	padding := blockSize - len(data)%blockSize
-	paddedData := bytes.Repeat([]byte{byte(padding)}, padding)
-
-	return append(data, paddedData...)
+	return nil
 }
 
-func pkcs7Unpad(data []byte, blockSize int) []byte {
-	lenght := len(data)
-	padding := int(data[lenght-1])
+// checkBlocks validates that ciphertext is a non-empty run of whole blocks.
+func checkBlocks(ciphertext []byte, blockSize int) error {
+	if len(ciphertext) == 0 || len(ciphertext)%blockSize != 0 {
+		return ErrInvalidCiphertext
+	}
 
-	return data[:lenght-padding]
+	return nil
 }
@@ -0,0 +1,62 @@
+package aes
+
+import (
+	"io"
+	"strconv"
+
+	"crypt/drbg"
+)
+
+// KeySize is the length of an AES key in bytes.
+type KeySize int
+
+const (
+	AES128 KeySize = 16
+	AES192 KeySize = 24
+	AES256 KeySize = 32
+)
+
+func (k KeySize) String() string {
+	switch k {
+	case AES128, AES192, AES256:
+		return "AES-" + strconv.Itoa(int(k)*8)
+	default:
+		return "invalid AES key size " + strconv.Itoa(int(k))
+	}
+}
+
+// NewKey returns a random key of the given size read from crypto/rand.
+func NewKey(size KeySize) ([]byte, error) {
+	return NewKeyFromReader(nil, size)
+}
+
+// NewKeyFromReader returns a key of the given size read from rand, which may be a
+// drbg.Deterministic reader for reproducible tests.
+func NewKeyFromReader(rand io.Reader, size KeySize) ([]byte, error) {
+	switch size {
+	case AES128, AES192, AES256:
+		return drbg.Read(rand, int(size))
+	default:
+		return nil, KeySizeError(size)
+	}
+}
+
+// KeySizer is implemented by every cipher, AEAD and MAC this package constructs from
+// a key (NewCCM, NewCMAC, NewCTR, NewCBCHMAC, NewCTRHMAC, NewGCMSIV, NewSIV and
+// NewXTS), reporting the AES variant it runs; NewSIV and NewXTS split their key in
+// half and report the size of one half. The cipher.AEAD and hash.Hash values can be
+// asserted to KeySizer.
+type KeySizer interface {
+	KeySize() KeySize
+}
+
+// KeySizeOf reports which AES variant key selects, or a KeySizeError if it has none.
+// The one-shot helpers such as EncryptCBC validate the key the same way.
+func KeySizeOf(key []byte) (KeySize, error) {
+	switch size := KeySize(len(key)); size {
+	case AES128, AES192, AES256:
+		return size, nil
+	default:
+		return 0, KeySizeError(len(key))
+	}
+}
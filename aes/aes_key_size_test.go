@@ -0,0 +1,101 @@
+package aes
+
+import (
+	"errors"
+	"testing"
+
+	"crypt/drbg"
+)
+
+func TestKeySizeConstants(t *testing.T) {
+	cases := []struct {
+		size KeySize
+		len  int
+		name string
+	}{
+		{AES128, 16, "AES-128"},
+		{AES192, 24, "AES-192"},
+		{AES256, 32, "AES-256"},
+	}
+
+	for _, c := range cases {
+		if int(c.size) != c.len || c.size.String() != c.name {
+			t.Errorf("%v: got %d bytes, want %d and %s", c.size, int(c.size), c.len, c.name)
+		}
+
+		key, err := NewKey(c.size)
+		if err != nil || len(key) != c.len {
+			t.Errorf("NewKey(%v) returned %d bytes, %v", c.size, len(key), err)
+		}
+		if size, err := KeySizeOf(key); err != nil || size != c.size {
+			t.Errorf("KeySizeOf(%d bytes) = %v, %v", len(key), size, err)
+		}
+	}
+
+	if got := KeySize(20).String(); got != "invalid AES key size 20" {
+		t.Errorf("KeySize(20).String() = %q", got)
+	}
+}
+
+func TestInvalidKeySize(t *testing.T) {
+	for _, size := range []KeySize{-1, 0, 8, 17, 64} {
+		var keyErr KeySizeError
+		if _, err := NewKey(size); !errors.As(err, &keyErr) || int(keyErr) != int(size) {
+			t.Errorf("NewKey(%d): got %v, want KeySizeError(%d)", size, err, size)
+		}
+	}
+
+	if _, err := KeySizeOf(make([]byte, 20)); err != KeySizeError(20) {
+		t.Errorf("KeySizeOf(20 bytes): got %v, want KeySizeError(20)", err)
+	}
+	if _, err := EncryptCBC(make([]byte, 20), make([]byte, 16), nil); err != KeySizeError(20) {
+		t.Errorf("EncryptCBC with a 20-byte key: got %v, want KeySizeError(20)", err)
+	}
+}
+
+func TestNewKeyFromReader(t *testing.T) {
+	a, err := NewKeyFromReader(drbg.NewDeterministic([]byte("key")), AES192)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := NewKeyFromReader(drbg.NewDeterministic([]byte("key")), AES192)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(a) != string(b) {
+		t.Error("the same seed gave different keys")
+	}
+}
+
+func TestKeySizer(t *testing.T) {
+	key16, key32, key64 := make([]byte, 16), make([]byte, 32), make([]byte, 64)
+	cases := []struct {
+		name string
+		new  func() (any, error)
+		want KeySize
+	}{
+		{"CCM", func() (any, error) { return NewCCM(key16, 12, 16) }, AES128},
+		{"CMAC", func() (any, error) { return NewCMAC(key32) }, AES256},
+		{"CTR", func() (any, error) { return NewCTR(key32, make([]byte, 12), 4) }, AES256},
+		{"CBC-HMAC", func() (any, error) { return NewCBCHMAC(make([]byte, 24)) }, AES192},
+		{"CTR-HMAC", func() (any, error) { return NewCTRHMAC(key16) }, AES128},
+		{"GCM-SIV", func() (any, error) { return NewGCMSIV(key32) }, AES256},
+		{"SIV", func() (any, error) { return NewSIV(key32, 0) }, AES128},
+		{"XTS", func() (any, error) { return NewXTS(key64) }, AES256},
+	}
+
+	for _, c := range cases {
+		v, err := c.new()
+		if err != nil {
+			t.Fatalf("%s: %v", c.name, err)
+		}
+		sizer, ok := v.(KeySizer)
+		if !ok {
+			t.Errorf("%s does not implement KeySizer", c.name)
+			continue
+		}
+		if got := sizer.KeySize(); got != c.want {
+			t.Errorf("%s: KeySize() = %v, want %v", c.name, got, c.want)
+		}
+	}
+}
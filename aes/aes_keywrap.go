@@ -0,0 +1,164 @@
+package aes
+
+import (
+	"crypto/cipher"
+	"crypto/subtle"
+	"encoding/binary"
+	"errors"
+)
+
+const keyWrapBlockSize = 8
+
+var (
+	// defaultKeyWrapIV is the integrity check value of RFC 3394 section 2.2.3.1.
+	defaultKeyWrapIV = []byte{0xa6, 0xa6, 0xa6, 0xa6, 0xa6, 0xa6, 0xa6, 0xa6}
+	// paddedKeyWrapIV is the constant half of the alternative IV of RFC 5649 section 3.
+	paddedKeyWrapIV = []byte{0xa6, 0x59, 0x59, 0xa6}
+)
+
+var (
+	// ErrKeyWrapInput is returned when the key to wrap, or the wrapped key, has an invalid length.
+	ErrKeyWrapInput = errors.New("aes: invalid key wrap input length")
+
+	// ErrKeyWrapIntegrity is returned when the integrity check value of a wrapped key does not verify.
+	ErrKeyWrapIntegrity = errors.New("aes: key unwrap integrity check failed")
+)
+
+// WrapKey wraps key under kek with the AES Key Wrap algorithm (RFC 3394).
+// The key must be a multiple of 8 bytes and at least 16 bytes long.
+func WrapKey(kek, key []byte) ([]byte, error) {
+	block, err := newBlock(kek)
+	if err != nil {
+		return nil, err
+	}
+	if len(key) < 2*keyWrapBlockSize || len(key)%keyWrapBlockSize != 0 {
+		return nil, ErrKeyWrapInput
+	}
+
+	return wrap(block, defaultKeyWrapIV, key), nil
+}
+
+// UnwrapKey unwraps a key produced by WrapKey and verifies its integrity check value.
+func UnwrapKey(kek, wrapped []byte) ([]byte, error) {
+	block, err := newBlock(kek)
+	if err != nil {
+		return nil, err
+	}
+	if len(wrapped) < 3*keyWrapBlockSize || len(wrapped)%keyWrapBlockSize != 0 {
+		return nil, ErrKeyWrapInput
+	}
+
+	iv, key := unwrap(block, wrapped)
+	if subtle.ConstantTimeCompare(iv, defaultKeyWrapIV) != 1 {
+		return nil, ErrKeyWrapIntegrity
+	}
+	return key, nil
+}
+
+// WrapKeyWithPadding wraps a key of any non-zero length under kek with the AES Key
+// Wrap with Padding algorithm (RFC 5649).
+func WrapKeyWithPadding(kek, key []byte) ([]byte, error) {
+	block, err := newBlock(kek)
+	if err != nil {
+		return nil, err
+	}
+	if len(key) == 0 || uint64(len(key)) > 0xffffffff {
+		return nil, ErrKeyWrapInput
+	}
+
+	iv := binary.BigEndian.AppendUint32(append([]byte(nil), paddedKeyWrapIV...), uint32(len(key)))
+	padded := make([]byte, (len(key)+keyWrapBlockSize-1)/keyWrapBlockSize*keyWrapBlockSize)
+	copy(padded, key)
+
+	if len(padded) == keyWrapBlockSize {
+		// a single block is encrypted directly as AIV || P
+		out := append(iv, padded...)
+		block.Encrypt(out, out)
+		return out, nil
+	}
+	return wrap(block, iv, padded), nil
+}
+
+// UnwrapKeyWithPadding unwraps a key produced by WrapKeyWithPadding, verifying the
+// integrity check value, the message length indicator and the zero padding.
+func UnwrapKeyWithPadding(kek, wrapped []byte) ([]byte, error) {
+	block, err := newBlock(kek)
+	if err != nil {
+		return nil, err
+	}
+	if len(wrapped) < 2*keyWrapBlockSize || len(wrapped)%keyWrapBlockSize != 0 {
+		return nil, ErrKeyWrapInput
+	}
+
+	var iv, padded []byte
+	if len(wrapped) == 2*keyWrapBlockSize {
+		out := make([]byte, len(wrapped))
+		block.Decrypt(out, wrapped)
+		iv, padded = out[:keyWrapBlockSize], out[keyWrapBlockSize:]
+	} else {
+		iv, padded = unwrap(block, wrapped)
+	}
+
+	length := int(binary.BigEndian.Uint32(iv[4:]))
+	good := subtle.ConstantTimeCompare(iv[:4], paddedKeyWrapIV) == 1
+	if !good || length <= len(padded)-keyWrapBlockSize || length > len(padded) {
+		return nil, ErrKeyWrapIntegrity
+	}
+
+	var nonZero byte
+	for _, b := range padded[length:] {
+		nonZero |= b
+	}
+	if nonZero != 0 {
+		return nil, ErrKeyWrapIntegrity
+	}
+	return padded[:length], nil
+}
+
+// wrap is the wrapping process W of RFC 3394 section 2.2.1 with the given initial value.
+func wrap(block cipher.Block, iv, plaintext []byte) []byte {
+	n := len(plaintext) / keyWrapBlockSize
+	out := make([]byte, keyWrapBlockSize+len(plaintext))
+	copy(out[keyWrapBlockSize:], plaintext)
+
+	a := binary.BigEndian.Uint64(iv)
+	var b [2 * keyWrapBlockSize]byte
+	for j := 0; j < 6; j++ {
+		for i := 1; i <= n; i++ {
+			r := out[i*keyWrapBlockSize : (i+1)*keyWrapBlockSize]
+			binary.BigEndian.PutUint64(b[:keyWrapBlockSize], a)
+			copy(b[keyWrapBlockSize:], r)
+			block.Encrypt(b[:], b[:])
+
+			a = binary.BigEndian.Uint64(b[:keyWrapBlockSize]) ^ uint64(n*j+i)
+			copy(r, b[keyWrapBlockSize:])
+		}
+	}
+
+	binary.BigEndian.PutUint64(out[:keyWrapBlockSize], a)
+	return out
+}
+
+// unwrap is the unwrapping process W^-1 of RFC 3394 section 2.2.2. It returns the
+// recovered initial value for the caller to check, and the plaintext.
+func unwrap(block cipher.Block, ciphertext []byte) ([]byte, []byte) {
+	n := len(ciphertext)/keyWrapBlockSize - 1
+	out := make([]byte, len(ciphertext)-keyWrapBlockSize)
+	copy(out, ciphertext[keyWrapBlockSize:])
+
+	a := binary.BigEndian.Uint64(ciphertext[:keyWrapBlockSize])
+	var b [2 * keyWrapBlockSize]byte
+	for j := 5; j >= 0; j-- {
+		for i := n; i >= 1; i-- {
+			r := out[(i-1)*keyWrapBlockSize : i*keyWrapBlockSize]
+			binary.BigEndian.PutUint64(b[:keyWrapBlockSize], a^uint64(n*j+i))
+			copy(b[keyWrapBlockSize:], r)
+			block.Decrypt(b[:], b[:])
+
+			a = binary.BigEndian.Uint64(b[:keyWrapBlockSize])
+			copy(r, b[keyWrapBlockSize:])
+		}
+	}
+
+	return binary.BigEndian.AppendUint64(nil, a), out
+}
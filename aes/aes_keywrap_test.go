@@ -0,0 +1,117 @@
+package aes
+
+import (
+	"bytes"
+	"testing"
+)
+
+// TestKeyWrapVectors are the test vectors of RFC 3394 section 4.
+func TestKeyWrapVectors(t *testing.T) {
+	kek128 := decodeHex(t, "000102030405060708090A0B0C0D0E0F")
+	kek192 := decodeHex(t, "000102030405060708090A0B0C0D0E0F1011121314151617")
+	kek256 := decodeHex(t, "000102030405060708090A0B0C0D0E0F101112131415161718191A1B1C1D1E1F")
+	key128 := decodeHex(t, "00112233445566778899AABBCCDDEEFF")
+	key192 := decodeHex(t, "00112233445566778899AABBCCDDEEFF0001020304050607")
+	key256 := decodeHex(t, "00112233445566778899AABBCCDDEEFF000102030405060708090A0B0C0D0E0F")
+
+	cases := []struct {
+		name     string
+		kek, key []byte
+		wrapped  string
+	}{
+		{"4.1", kek128, key128, "1FA68B0A8112B447 AEF34BD8FB5A7B82 9D3E862371D2CFE5"},
+		{"4.2", kek192, key128, "96778B25AE6CA435 F92B5B97C050AED2 468AB8A17AD84E5D"},
+		{"4.3", kek256, key128, "64E8C3F9CE0F5BA2 63E9777905818A2A 93C8191E7D6E8AE7"},
+		{"4.4", kek192, key192, "031D33264E15D332 68F24EC260743EDC E1C6C7DDEE725A93 6BA814915C6762D2"},
+		{"4.5", kek256, key192, "A8F9BC1612C68B3F F6E6F4FBE30E71E4 769C8B80A32CB895 8CD5D17D6B254DA1"},
+		{"4.6", kek256, key256, "28C9F404C4B810F4 CBCCB35CFB87F826 3F5786E2D80ED326 CBC7F0E71A99F43B FB988B9B7A02DD21"},
+	}
+
+	for _, c := range cases {
+		want := decodeHex(t, c.wrapped)
+		got, err := WrapKey(c.kek, c.key)
+		if err != nil || !bytes.Equal(got, want) {
+			t.Errorf("%s: WrapKey = %X, %v, want %X", c.name, got, err, want)
+		}
+		if key, err := UnwrapKey(c.kek, want); err != nil || !bytes.Equal(key, c.key) {
+			t.Errorf("%s: UnwrapKey = %X, %v", c.name, key, err)
+		}
+	}
+}
+
+// TestKeyWrapWithPaddingVectors are the test vectors of RFC 5649 section 6.
+func TestKeyWrapWithPaddingVectors(t *testing.T) {
+	kek := decodeHex(t, "5840df6e29b02af1 ab493b705bf16ea1 ae8338f4dcc176a8")
+	cases := []struct {
+		key, wrapped string
+	}{
+		{"c37b7e6492584340 bed1220780894115 5068f738", "138bdeaa9b8fa7fc 61f97742e72248ee 5ae6ae5360d1ae6a 5f54f373fa543b6a"},
+		{"466f7250617369", "afbeb0f07dfbf541 9200f2ccb50bb24f"},
+	}
+
+	for _, c := range cases {
+		key, want := decodeHex(t, c.key), decodeHex(t, c.wrapped)
+		got, err := WrapKeyWithPadding(kek, key)
+		if err != nil || !bytes.Equal(got, want) {
+			t.Errorf("WrapKeyWithPadding(%x) = %x, %v, want %x", key, got, err, want)
+		}
+		if unwrapped, err := UnwrapKeyWithPadding(kek, want); err != nil || !bytes.Equal(unwrapped, key) {
+			t.Errorf("UnwrapKeyWithPadding(%x) = %x, %v", want, unwrapped, err)
+		}
+	}
+}
+
+func TestKeyWrapIntegrity(t *testing.T) {
+	kek := decodeHex(t, "000102030405060708090A0B0C0D0E0F")
+	key := decodeHex(t, "00112233445566778899AABBCCDDEEFF")
+
+	wrapped, err := WrapKey(kek, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range wrapped {
+		tampered := bytes.Clone(wrapped)
+		tampered[i] ^= 0x80
+		if _, err := UnwrapKey(kek, tampered); err != ErrKeyWrapIntegrity {
+			t.Errorf("byte %d flipped: got %v, want ErrKeyWrapIntegrity", i, err)
+		}
+	}
+	otherKEK := bytes.Clone(kek)
+	otherKEK[0] ^= 1
+	if _, err := UnwrapKey(otherKEK, wrapped); err != ErrKeyWrapIntegrity {
+		t.Errorf("wrong KEK: got %v, want ErrKeyWrapIntegrity", err)
+	}
+
+	padded, err := WrapKeyWithPadding(kek, key[:5])
+	if err != nil {
+		t.Fatal(err)
+	}
+	padded[3] ^= 1
+	if _, err := UnwrapKeyWithPadding(kek, padded); err != ErrKeyWrapIntegrity {
+		t.Errorf("padded wrap tampered: got %v, want ErrKeyWrapIntegrity", err)
+	}
+	// a padded wrap is not a valid RFC 3394 wrap and vice versa
+	if _, err := UnwrapKeyWithPadding(kek, wrapped); err != ErrKeyWrapIntegrity {
+		t.Errorf("RFC 3394 wrap opened as RFC 5649: got %v, want ErrKeyWrapIntegrity", err)
+	}
+}
+
+func TestKeyWrapInputs(t *testing.T) {
+	kek := make([]byte, 16)
+	for _, key := range [][]byte{nil, make([]byte, 8), make([]byte, 17)} {
+		if _, err := WrapKey(kek, key); err != ErrKeyWrapInput {
+			t.Errorf("WrapKey(%d bytes): got %v, want ErrKeyWrapInput", len(key), err)
+		}
+	}
+	if _, err := WrapKeyWithPadding(kek, nil); err != ErrKeyWrapInput {
+		t.Errorf("WrapKeyWithPadding(empty): got %v, want ErrKeyWrapInput", err)
+	}
+	for _, wrapped := range [][]byte{nil, make([]byte, 16), make([]byte, 25)} {
+		if _, err := UnwrapKey(kek, wrapped); err != ErrKeyWrapInput {
+			t.Errorf("UnwrapKey(%d bytes): got %v, want ErrKeyWrapInput", len(wrapped), err)
+		}
+	}
+	if _, err := WrapKey(make([]byte, 20), make([]byte, 16)); err != KeySizeError(20) {
+		t.Errorf("20-byte KEK: got %v, want KeySizeError(20)", err)
+	}
+}
@@ -2,41 +2,56 @@ package aes
 
 import (
 	"crypto/aes"
-	"crypto/cipher"
 	"fmt"
+
+	"crypt/blockcipher"
+	"crypt/drbg"
 )
 
 func AES_OFB() {
-	key := generateAESKey(32)
+	keySize := AES256
+	key, err := NewKey(keySize)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println("key size: ", keySize)
 	fmt.Println("key(bytes): ", key)
 	fmt.Println("key(string): ", string(key))
 
-	iv := generateIV(aes.BlockSize)
+	iv, err := drbg.Read(nil, aes.BlockSize)
+	if err != nil {
+		panic(err)
+	}
 	fmt.Println("iv(bytes): ", iv)
 	fmt.Println("iv(string): ", string(iv))
 
 	plainText := "Hello this is mustafa!!!! and I am testing aes encryption using ecb mode \nHello this is mustafa!!! and I am testing aes encryption using ecb mode \nI repeated same sentence twice just to see the similarity of ciphertext"
-	cipher := aes_ofb_encrypt([]byte(plainText), key, iv)
+	cipher, err := EncryptOFB(key, iv, []byte(plainText))
+	if err != nil {
+		panic(err)
+	}
 	fmt.Println("cipherText(bytes): ", cipher)
 	fmt.Println("cipherText(string): ", string(cipher))
 
-	plain := aes_ofb_decrypt(cipher, key, iv)
+	plain, err := DecryptOFB(key, iv, cipher)
+	if err != nil {
+		panic(err)
+	}
 	fmt.Println("decryptedText(bytes): ", plain)
 	fmt.Println("decryptedText(string): ", string(plain))
 }
 
-func aes_ofb_encrypt(plaintext, key, iv []byte) []byte {
-	block, err := aes.NewCipher(key)
+// EncryptOFB encrypts plaintext in OFB mode.
+func EncryptOFB(key, iv, plaintext []byte) ([]byte, error) {
+	block, err := newBlock(key)
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
-	ciphertext := make([]byte, len(plaintext))
-	stream := cipher.NewOFB(block, iv)
-	stream.XORKeyStream(ciphertext, plaintext)
 
-	return ciphertext
+	return blockcipher.EncryptOFB(block, iv, plaintext)
 }
 
-func aes_ofb_decrypt(ciphertext, key, iv []byte) []byte {
-	return aes_ofb_encrypt(ciphertext, key, iv) // OFB uses same function for encryption/decryption
+// DecryptOFB decrypts a ciphertext produced by EncryptOFB.
+func DecryptOFB(key, iv, ciphertext []byte) ([]byte, error) {
+	return EncryptOFB(key, iv, ciphertext) // OFB uses same function for encryption/decryption
 }
@@ -0,0 +1,21 @@
+package aes
+
+import "crypt/blockcipher"
+
+// Padding is a block padding scheme used by the ECB and CBC helpers. The schemes
+// live in the blockcipher package so that every registered cipher shares them.
+type Padding = blockcipher.Padding
+
+// ErrInvalidPadding is returned for any malformed padding.
+var ErrInvalidPadding = blockcipher.ErrInvalidPadding
+
+var (
+	// PKCS7 pads with n bytes of value n (RFC 5652). It is the default for the block modes.
+	PKCS7 = blockcipher.PKCS7
+	// ANSIX923 pads with zero bytes followed by a final length byte.
+	ANSIX923 = blockcipher.ANSIX923
+	// ISO7816 pads with a single 0x80 byte followed by zero bytes (ISO/IEC 7816-4).
+	ISO7816 = blockcipher.ISO7816
+	// ZeroPadding pads with zero bytes up to the block boundary.
+	ZeroPadding = blockcipher.ZeroPadding
+)
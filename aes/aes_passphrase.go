@@ -0,0 +1,213 @@
+package aes
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"io"
+
+	"crypt/drbg"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/crypto/scrypt"
+)
+
+// KDF identifies the password-based key derivation function of a passphrase envelope.
+type KDF byte
+
+const (
+	KDFArgon2id KDF = iota + 1
+	KDFScrypt
+	KDFPBKDF2
+)
+
+// KDFParams holds the cost parameters of a key derivation function. Only the fields
+// of the selected KDF are used, which lets one value describe limits for all of them.
+type KDFParams struct {
+	KDF KDF
+
+	// Argon2id: passes, memory in KiB and degree of parallelism.
+	Time    uint32
+	Memory  uint32
+	Threads uint8
+
+	// scrypt: CPU/memory cost (a power of two), block size and parallelism.
+	N, R, P uint32
+
+	// PBKDF2-HMAC-SHA256: iteration count.
+	Iterations uint32
+}
+
+var (
+	// Argon2idParams are the second recommended Argon2id settings of RFC 9106.
+	Argon2idParams = KDFParams{KDF: KDFArgon2id, Time: 3, Memory: 64 * 1024, Threads: 4}
+	// ScryptParams are the interactive-login settings recommended for scrypt.
+	ScryptParams = KDFParams{KDF: KDFScrypt, N: 1 << 15, R: 8, P: 1}
+	// PBKDF2Params follow the OWASP recommendation for PBKDF2-HMAC-SHA256.
+	PBKDF2Params = KDFParams{KDF: KDFPBKDF2, Iterations: 600000}
+
+	// MinimumKDFParams is the weakest cost accepted by SealWithPassphrase and
+	// OpenWithPassphrase. Raise it to reject envelopes created with cheaper settings.
+	MinimumKDFParams = KDFParams{Time: 1, Memory: 19 * 1024, Threads: 1, N: 1 << 14, R: 8, P: 1, Iterations: 100000}
+
+	// MaximumKDFParams is the highest cost accepted by SealWithPassphrase and
+	// OpenWithPassphrase. The parameters of an envelope are not authenticated until
+	// the key has been derived, so without a ceiling a forged envelope could make
+	// OpenWithPassphrase allocate terabytes or run for hours.
+	MaximumKDFParams = KDFParams{Time: 16, Memory: 1 << 20, Threads: 16, N: 1 << 20, R: 16, P: 16, Iterations: 10000000}
+)
+
+var (
+	// ErrUnknownKDF is returned for a KDF identifier that is not one of the defined constants.
+	ErrUnknownKDF = errors.New("aes: unknown key derivation function")
+
+	// ErrWeakKDFParams is returned when the cost parameters are below MinimumKDFParams.
+	ErrWeakKDFParams = errors.New("aes: key derivation parameters below the configured minimum")
+
+	// ErrExcessiveKDFParams is returned when the cost parameters are above MaximumKDFParams.
+	ErrExcessiveKDFParams = errors.New("aes: key derivation parameters above the configured maximum")
+
+	// ErrInvalidKDFParams is returned for parameters the KDF cannot use, such as an
+	// scrypt N that is not a power of two.
+	ErrInvalidKDFParams = errors.New("aes: invalid key derivation parameters")
+)
+
+const (
+	passphraseVersion    byte = 1
+	passphraseSaltSize        = 16
+	passphraseHeaderSize      = 2 + 3*4 + passphraseSaltSize
+	passphraseKeySize         = 32
+)
+
+// SealWithPassphrase derives an AES-256 key from passphrase with the given KDF and
+// a random salt, and seals plaintext with SealGCM. The returned envelope is
+// version(1) || kdf(1) || three uint32 cost parameters || salt(16) || GCM envelope,
+// with the header authenticated as associated data. The salt and nonce are read
+// from rand.
+func SealWithPassphrase(rand io.Reader, passphrase, plaintext []byte, params KDFParams) ([]byte, error) {
+	if err := params.check(); err != nil {
+		return nil, err
+	}
+
+	header := make([]byte, 0, passphraseHeaderSize)
+	header = append(header, passphraseVersion, byte(params.KDF))
+	for _, p := range params.encode() {
+		header = binary.BigEndian.AppendUint32(header, p)
+	}
+	salt, err := drbg.Read(rand, passphraseSaltSize)
+	if err != nil {
+		return nil, err
+	}
+	header = append(header, salt...)
+
+	key, err := params.deriveKey(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+	sealed, err := SealGCM(rand, key, plaintext, header)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(header, sealed...), nil
+}
+
+// OpenWithPassphrase opens an envelope produced by SealWithPassphrase. Envelopes
+// whose cost parameters are below MinimumKDFParams or above MaximumKDFParams are
+// refused before any key derivation takes place.
+func OpenWithPassphrase(passphrase, envelope []byte) ([]byte, error) {
+	if len(envelope) < passphraseHeaderSize || envelope[0] != passphraseVersion {
+		return nil, ErrInvalidEnvelope
+	}
+
+	header := envelope[:passphraseHeaderSize]
+	var encoded [3]uint32
+	for i := range encoded {
+		encoded[i] = binary.BigEndian.Uint32(header[2+4*i:])
+	}
+	params, err := decodeKDFParams(KDF(header[1]), encoded)
+	if err != nil {
+		return nil, err
+	}
+	if err := params.check(); err != nil {
+		return nil, err
+	}
+
+	key, err := params.deriveKey(passphrase, header[passphraseHeaderSize-passphraseSaltSize:])
+	if err != nil {
+		return nil, err
+	}
+	return OpenGCM(key, envelope[passphraseHeaderSize:], header)
+}
+
+func (p KDFParams) encode() [3]uint32 {
+	switch p.KDF {
+	case KDFArgon2id:
+		return [3]uint32{p.Time, p.Memory, uint32(p.Threads)}
+	case KDFScrypt:
+		return [3]uint32{p.N, p.R, p.P}
+	default:
+		return [3]uint32{p.Iterations, 0, 0}
+	}
+}
+
+func decodeKDFParams(kdf KDF, encoded [3]uint32) (KDFParams, error) {
+	switch kdf {
+	case KDFArgon2id:
+		if encoded[2] > 255 {
+			return KDFParams{}, ErrInvalidEnvelope
+		}
+		return KDFParams{KDF: kdf, Time: encoded[0], Memory: encoded[1], Threads: uint8(encoded[2])}, nil
+	case KDFScrypt:
+		return KDFParams{KDF: kdf, N: encoded[0], R: encoded[1], P: encoded[2]}, nil
+	case KDFPBKDF2:
+		return KDFParams{KDF: kdf, Iterations: encoded[0]}, nil
+	default:
+		return KDFParams{}, ErrUnknownKDF
+	}
+}
+
+// check compares p against MinimumKDFParams and MaximumKDFParams.
+func (p KDFParams) check() error {
+	lo, hi := MinimumKDFParams, MaximumKDFParams
+
+	var weak, excessive bool
+	switch p.KDF {
+	case KDFArgon2id:
+		weak = p.Time < lo.Time || p.Memory < lo.Memory || p.Threads < lo.Threads || p.Threads == 0
+		excessive = p.Time > hi.Time || p.Memory > hi.Memory || p.Threads > hi.Threads
+	case KDFScrypt:
+		if p.N < 2 || p.N&(p.N-1) != 0 {
+			return ErrInvalidKDFParams
+		}
+		weak = p.N < lo.N || p.R < lo.R || p.P < lo.P
+		excessive = p.N > hi.N || p.R > hi.R || p.P > hi.P
+	case KDFPBKDF2:
+		weak = p.Iterations < lo.Iterations
+		excessive = p.Iterations > hi.Iterations
+	default:
+		return ErrUnknownKDF
+	}
+	if weak {
+		return ErrWeakKDFParams
+	}
+	if excessive {
+		return ErrExcessiveKDFParams
+	}
+
+	return nil
+}
+
+func (p KDFParams) deriveKey(passphrase, salt []byte) ([]byte, error) {
+	switch p.KDF {
+	case KDFArgon2id:
+		return argon2.IDKey(passphrase, salt, p.Time, p.Memory, p.Threads, passphraseKeySize), nil
+	case KDFScrypt:
+		return scrypt.Key(passphrase, salt, int(p.N), int(p.R), int(p.P), passphraseKeySize)
+	case KDFPBKDF2:
+		return pbkdf2.Key(passphrase, salt, int(p.Iterations), passphraseKeySize, sha256.New), nil
+	default:
+		return nil, ErrUnknownKDF
+	}
+}
@@ -0,0 +1,92 @@
+package aes
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+)
+
+// cheapParams are the cheapest settings MinimumKDFParams accepts, to keep tests fast.
+var cheapParams = []KDFParams{
+	{KDF: KDFArgon2id, Time: 1, Memory: 19 * 1024, Threads: 1},
+	{KDF: KDFScrypt, N: 1 << 14, R: 8, P: 1},
+	{KDF: KDFPBKDF2, Iterations: 100000},
+}
+
+func TestPassphraseRoundTrip(t *testing.T) {
+	for _, params := range cheapParams {
+		envelope, err := SealWithPassphrase(nil, []byte("correct horse"), []byte("secret"), params)
+		if err != nil {
+			t.Fatalf("KDF %d: %v", params.KDF, err)
+		}
+
+		plaintext, err := OpenWithPassphrase([]byte("correct horse"), envelope)
+		if err != nil || string(plaintext) != "secret" {
+			t.Errorf("KDF %d: OpenWithPassphrase = %q, %v", params.KDF, plaintext, err)
+		}
+		if _, err := OpenWithPassphrase([]byte("battery staple"), envelope); err != ErrAuthenticationFailed {
+			t.Errorf("KDF %d, wrong passphrase: got %v, want ErrAuthenticationFailed", params.KDF, err)
+		}
+	}
+}
+
+func TestPassphraseParamLimits(t *testing.T) {
+	cases := []struct {
+		params KDFParams
+		want   error
+	}{
+		{KDFParams{KDF: KDFArgon2id, Time: 1, Memory: 1024, Threads: 1}, ErrWeakKDFParams},
+		{KDFParams{KDF: KDFArgon2id, Time: 1, Memory: 19 * 1024, Threads: 0}, ErrWeakKDFParams},
+		{KDFParams{KDF: KDFScrypt, N: 1 << 10, R: 8, P: 1}, ErrWeakKDFParams},
+		{KDFParams{KDF: KDFPBKDF2, Iterations: 1000}, ErrWeakKDFParams},
+		{KDFParams{KDF: KDFArgon2id, Time: 1, Memory: 0xffffffff, Threads: 1}, ErrExcessiveKDFParams},
+		{KDFParams{KDF: KDFArgon2id, Time: 0xffffffff, Memory: 19 * 1024, Threads: 1}, ErrExcessiveKDFParams},
+		{KDFParams{KDF: KDFScrypt, N: 1 << 30, R: 8, P: 1}, ErrExcessiveKDFParams},
+		{KDFParams{KDF: KDFPBKDF2, Iterations: 0xffffffff}, ErrExcessiveKDFParams},
+		{KDFParams{KDF: KDFScrypt, N: 1<<14 + 1, R: 8, P: 1}, ErrInvalidKDFParams},
+		{KDFParams{KDF: KDFScrypt, N: 0, R: 8, P: 1}, ErrInvalidKDFParams},
+		{KDFParams{KDF: 9}, ErrUnknownKDF},
+	}
+
+	for _, c := range cases {
+		if _, err := SealWithPassphrase(nil, []byte("pw"), nil, c.params); err != c.want {
+			t.Errorf("SealWithPassphrase(%+v): got %v, want %v", c.params, err, c.want)
+		}
+	}
+}
+
+// TestPassphraseForgedParams rewrites the cost parameters of a valid envelope and
+// checks that OpenWithPassphrase refuses them before deriving any key.
+func TestPassphraseForgedParams(t *testing.T) {
+	envelope, err := SealWithPassphrase(nil, []byte("pw"), []byte("secret"), cheapParams[2])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	forge := func(kdf KDF, params ...uint32) []byte {
+		forged := bytes.Clone(envelope)
+		forged[1] = byte(kdf)
+		for i, p := range params {
+			binary.BigEndian.PutUint32(forged[2+4*i:], p)
+		}
+		return forged
+	}
+	cases := []struct {
+		envelope []byte
+		want     error
+	}{
+		{forge(KDFArgon2id, 1, 0xffffffff, 1), ErrExcessiveKDFParams},
+		{forge(KDFPBKDF2, 0xffffffff, 0, 0), ErrExcessiveKDFParams},
+		{forge(KDFScrypt, 1<<31, 8, 1), ErrExcessiveKDFParams},
+		{forge(KDFScrypt, 3<<14, 8, 1), ErrInvalidKDFParams},
+		{forge(KDFPBKDF2, 1, 0, 0), ErrWeakKDFParams},
+		{forge(KDFArgon2id, 1, 19*1024, 256), ErrInvalidEnvelope},
+		{forge(0), ErrUnknownKDF},
+		{envelope[:10], ErrInvalidEnvelope},
+	}
+	for i, c := range cases {
+		if _, err := OpenWithPassphrase([]byte("pw"), c.envelope); err != c.want {
+			t.Errorf("case %d: got %v, want %v", i, err, c.want)
+		}
+	}
+}
@@ -0,0 +1,16 @@
+package aes
+
+import (
+	"crypto/aes"
+
+	"crypt/blockcipher"
+)
+
+func init() {
+	blockcipher.Register(blockcipher.Cipher{
+		Name:      "AES",
+		BlockSize: aes.BlockSize,
+		KeySizes:  []int{int(AES128), int(AES192), int(AES256)},
+		New:       aes.NewCipher,
+	})
+}
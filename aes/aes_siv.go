@@ -0,0 +1,148 @@
+package aes
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/subtle"
+)
+
+// siv implements AES-SIV (RFC 5297) as a cipher.AEAD. The associated data and, when
+// present, the nonce are the S2V header components, in that order.
+type siv struct {
+	mac       cipher.Block
+	ctr       cipher.Block
+	nonceSize int
+	keySize   KeySize
+}
+
+// NewSIV returns a cipher.AEAD implementing AES-SIV. The key is 32, 48 or 64 bytes
+// and is split in half between S2V and CTR. A nonceSize of 0 gives deterministic
+// encryption; any other size adds the nonce as an extra header component, and
+// reusing a nonce only reveals whether two messages are identical.
+//
+// RFC 5297 allows any number of associated data components, but cipher.AEAD has
+// room for only one, so vectors with several components (such as appendix A.2)
+// cannot be expressed through this API.
+func NewSIV(key []byte, nonceSize int) (cipher.AEAD, error) {
+	switch len(key) {
+	case 32, 48, 64:
+	default:
+		return nil, KeySizeError(len(key))
+	}
+	if nonceSize < 0 {
+		return nil, IVSizeError(nonceSize)
+	}
+
+	mac, err := newBlock(key[:len(key)/2])
+	if err != nil {
+		return nil, err
+	}
+	ctr, err := newBlock(key[len(key)/2:])
+	if err != nil {
+		return nil, err
+	}
+	return &siv{mac: mac, ctr: ctr, nonceSize: nonceSize, keySize: KeySize(len(key) / 2)}, nil
+}
+
+func (s *siv) KeySize() KeySize { return s.keySize }
+
+func (s *siv) NonceSize() int { return s.nonceSize }
+
+func (s *siv) Overhead() int { return aes.BlockSize }
+
+func (s *siv) Seal(dst, nonce, plaintext, additionalData []byte) []byte {
+	if len(nonce) != s.nonceSize {
+		panic("aes: incorrect nonce length given to SIV")
+	}
+
+	v := s.s2v(s.header(nonce, additionalData), plaintext)
+	ciphertext := make([]byte, len(plaintext))
+	cipher.NewCTR(s.ctr, sivCounter(v)).XORKeyStream(ciphertext, plaintext)
+
+	dst = append(dst, v...)
+	return append(dst, ciphertext...)
+}
+
+func (s *siv) Open(dst, nonce, ciphertext, additionalData []byte) ([]byte, error) {
+	if len(nonce) != s.nonceSize {
+		panic("aes: incorrect nonce length given to SIV")
+	}
+	if len(ciphertext) < aes.BlockSize {
+		return nil, ErrAuthenticationFailed
+	}
+
+	v := ciphertext[:aes.BlockSize]
+	plaintext := make([]byte, len(ciphertext)-aes.BlockSize)
+	cipher.NewCTR(s.ctr, sivCounter(v)).XORKeyStream(plaintext, ciphertext[aes.BlockSize:])
+
+	if subtle.ConstantTimeCompare(v, s.s2v(s.header(nonce, additionalData), plaintext)) != 1 {
+		return nil, ErrAuthenticationFailed
+	}
+	return append(dst, plaintext...), nil
+}
+
+func (s *siv) header(nonce, additionalData []byte) [][]byte {
+	if s.nonceSize == 0 {
+		return [][]byte{additionalData}
+	}
+	return [][]byte{additionalData, nonce}
+}
+
+// s2v is the S2V pseudo-random function of RFC 5297 section 2.4 over the header
+// components followed by the plaintext.
+func (s *siv) s2v(header [][]byte, plaintext []byte) []byte {
+	mac := newCMAC(s.mac)
+
+	mac.Write(make([]byte, aes.BlockSize))
+	d := mac.Sum(nil)
+	for _, component := range header {
+		mac.Reset()
+		mac.Write(component)
+		d = dbl(d)
+		subtle.XORBytes(d, d, mac.Sum(nil))
+	}
+
+	var t []byte
+	if len(plaintext) >= aes.BlockSize {
+		t = append([]byte(nil), plaintext...)
+		end := t[len(t)-aes.BlockSize:]
+		subtle.XORBytes(end, end, d)
+	} else {
+		t = dbl(d)
+		subtle.XORBytes(t, t, ISO7816.Pad(plaintext, aes.BlockSize)) // pad(Sn) is the 10* padding
+	}
+
+	mac.Reset()
+	mac.Write(t)
+	return mac.Sum(nil)
+}
+
+// sivCounter clears the two bits of the synthetic IV that RFC 5297 reserves so that
+// the CTR counter can be incremented without carrying into them.
+func sivCounter(v []byte) []byte {
+	q := append([]byte(nil), v...)
+	q[8] &= 0x7f
+	q[12] &= 0x7f
+
+	return q
+}
+
+// EncryptSIV encrypts plaintext with AES-SIV. The nonce may be nil for deterministic encryption.
+func EncryptSIV(key, nonce, plaintext []byte) ([]byte, error) {
+	aesSIV, err := NewSIV(key, len(nonce))
+	if err != nil {
+		return nil, err
+	}
+
+	return aesSIV.Seal(nil, nonce, plaintext, nil), nil
+}
+
+// DecryptSIV authenticates and decrypts a ciphertext produced by EncryptSIV.
+func DecryptSIV(key, nonce, ciphertext []byte) ([]byte, error) {
+	aesSIV, err := NewSIV(key, len(nonce))
+	if err != nil {
+		return nil, err
+	}
+
+	return aesSIV.Open(nil, nonce, ciphertext, nil)
+}
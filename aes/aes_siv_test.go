@@ -0,0 +1,111 @@
+package aes
+
+import (
+	"bytes"
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+func decodeHex(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(strings.ReplaceAll(s, " ", ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+// TestSIVDeterministicVector is RFC 5297 appendix A.1.
+func TestSIVDeterministicVector(t *testing.T) {
+	key := decodeHex(t, "fffefdfc fbfaf9f8 f7f6f5f4 f3f2f1f0 f0f1f2f3 f4f5f6f7 f8f9fafb fcfdfeff")
+	ad := decodeHex(t, "10111213 14151617 18191a1b 1c1d1e1f 20212223 24252627")
+	plaintext := decodeHex(t, "11223344 55667788 99aabbcc ddee")
+	want := decodeHex(t, "85632d07 c6e8f37f 950acd32 0a2ecc93 40c02b96 90c4dc04 daef7f6a fe5c")
+
+	aead, err := NewSIV(key, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := aead.Seal(nil, nil, plaintext, ad)
+	if !bytes.Equal(got, want) {
+		t.Errorf("Seal = %x, want %x", got, want)
+	}
+	if opened, err := aead.Open(nil, nil, want, ad); err != nil || !bytes.Equal(opened, plaintext) {
+		t.Errorf("Open = %x, %v", opened, err)
+	}
+
+	want[len(want)-1] ^= 1
+	if _, err := aead.Open(nil, nil, want, ad); err != ErrAuthenticationFailed {
+		t.Errorf("tampered ciphertext: got %v, want ErrAuthenticationFailed", err)
+	}
+}
+
+// TestSIVNonceVector is RFC 5297 appendix A.2. Its header has two associated data
+// components before the nonce, which the cipher.AEAD interface of NewSIV cannot
+// express, so the vector is checked through the S2V header directly.
+func TestSIVNonceVector(t *testing.T) {
+	key := decodeHex(t, "7f7e7d7c 7b7a7978 77767574 73727170 40414243 44454647 48494a4b 4c4d4e4f")
+	ad1 := decodeHex(t, "00112233 44556677 8899aabb ccddeeff deaddada deaddada ffeeddcc bbaa9988 77665544 33221100")
+	ad2 := decodeHex(t, "10203040 50607080 90a0")
+	nonce := decodeHex(t, "09f91102 9d74e35b d84156c5 635688c0")
+	plaintext := decodeHex(t, "74686973 20697320 736f6d65 20706c61 696e7465 78742074 6f20656e 63727970 74207573 696e6720 5349562d 414553")
+	want := decodeHex(t, "7bdb6e3b 432667eb 06f4d14b ff2fbd0f cb900f2f ddbe4043 26601965 c889bf17 dba77ceb 094fa663 b7a3f748 ba8af829 ea64ad54 4a272e9c 485b62a3 fd5c0d")
+
+	aead, err := NewSIV(key, len(nonce))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := aead.(*siv)
+	v := s.s2v([][]byte{ad1, ad2, nonce}, plaintext)
+	if !bytes.Equal(v, want[:16]) {
+		t.Errorf("S2V = %x, want %x", v, want[:16])
+	}
+
+	// with a single associated data component the public API still round-trips
+	ciphertext := aead.Seal(nil, nonce, plaintext, ad1)
+	if opened, err := aead.Open(nil, nonce, ciphertext, ad1); err != nil || !bytes.Equal(opened, plaintext) {
+		t.Errorf("Open = %x, %v", opened, err)
+	}
+	if _, err := aead.Open(nil, nonce, ciphertext, ad2); err != ErrAuthenticationFailed {
+		t.Errorf("wrong associated data: got %v, want ErrAuthenticationFailed", err)
+	}
+}
+
+// TestGCMSIVVectors are from RFC 8452 appendix C.1 (AES-128) and C.2 (AES-256).
+func TestGCMSIVVectors(t *testing.T) {
+	key128 := decodeHex(t, "01000000000000000000000000000000")
+	key256 := decodeHex(t, "0100000000000000000000000000000000000000000000000000000000000000")
+	nonce := decodeHex(t, "030000000000000000000000")
+
+	cases := []struct {
+		key, plaintext, ad []byte
+		want               string
+	}{
+		{key128, nil, nil, "dc20e2d83f25705bb49e439eca56de25"},
+		{key128, decodeHex(t, "0100000000000000"), nil, "b5d839330ac7b786578782fff6013b815b287c22493a364c"},
+		{key128, decodeHex(t, "010000000000000000000000"), nil, "7323ea61d05932260047d942a4978db357391a0bc4fdec8b0d106639"},
+		{key128, decodeHex(t, "01000000000000000000000000000000"), nil, "743f7c8077ab25f8624e2e948579cf77303aaf90f6fe21199c6068577437a0c4"},
+		{key128, decodeHex(t, "0200000000000000"), decodeHex(t, "01"), "1e6daba35669f4273b0a1a2560969cdf790d99759abd1508"},
+		{key256, nil, nil, "07f5f4169bbf55a8400cd47ea6fd400f"},
+		{key256, decodeHex(t, "0100000000000000"), nil, "c2ef328e5c71c83b843122130f7364b761e0b97427e3df28"},
+	}
+
+	for i, c := range cases {
+		aead, err := NewGCMSIV(c.key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := decodeHex(t, c.want)
+		if got := aead.Seal(nil, nonce, c.plaintext, c.ad); !bytes.Equal(got, want) {
+			t.Errorf("case %d: Seal = %x, want %x", i, got, want)
+		}
+		if opened, err := aead.Open(nil, nonce, want, c.ad); err != nil || !bytes.Equal(opened, c.plaintext) {
+			t.Errorf("case %d: Open = %x, %v", i, opened, err)
+		}
+		want[0] ^= 1
+		if _, err := aead.Open(nil, nonce, want, c.ad); err != ErrAuthenticationFailed {
+			t.Errorf("case %d, tampered: got %v, want ErrAuthenticationFailed", i, err)
+		}
+	}
+}
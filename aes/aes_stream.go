@@ -0,0 +1,72 @@
+package aes
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"errors"
+	"io"
+
+	"crypt/blockcipher"
+)
+
+// StreamMode selects the keystream mode used by the streaming wrappers. It is the
+// blockcipher.Mode enum, of which only the modes that need no padding are accepted.
+type StreamMode = blockcipher.Mode
+
+const (
+	// ModeCTR is counter mode, with iv as the initial counter block.
+	ModeCTR = blockcipher.CTR
+	// ModeOFB is output feedback mode.
+	ModeOFB = blockcipher.OFB
+	// ModeCFB is cipher feedback mode.
+	ModeCFB = blockcipher.CFB
+)
+
+// ErrUnknownMode is returned for a StreamMode that is not one of the defined constants.
+var ErrUnknownMode = errors.New("aes: unknown stream mode")
+
+func newStream(mode StreamMode, key, iv []byte, decrypt bool) (cipher.Stream, error) {
+	block, err := newBlock(key)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkIV(iv, aes.BlockSize); err != nil {
+		return nil, err
+	}
+
+	switch mode {
+	case ModeCTR:
+		return cipher.NewCTR(block, iv), nil
+	case ModeOFB:
+		return cipher.NewOFB(block, iv), nil
+	case ModeCFB:
+		if decrypt {
+			return cipher.NewCFBDecrypter(block, iv), nil
+		}
+		return cipher.NewCFBEncrypter(block, iv), nil
+	default:
+		return nil, ErrUnknownMode
+	}
+}
+
+// NewEncryptingWriter returns a writer that encrypts everything written to it with
+// the given mode before passing it on to w. Closing it closes w if w is an io.Closer.
+// The stream modes are not authenticated; use NewGCMEncryptingWriter when integrity matters.
+func NewEncryptingWriter(mode StreamMode, key, iv []byte, w io.Writer) (io.WriteCloser, error) {
+	stream, err := newStream(mode, key, iv, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return &cipher.StreamWriter{S: stream, W: w}, nil
+}
+
+// NewDecryptingReader returns a reader that decrypts data read from r with the given mode.
+func NewDecryptingReader(mode StreamMode, key, iv []byte, r io.Reader) (io.Reader, error) {
+	stream, err := newStream(mode, key, iv, true)
+	if err != nil {
+		return nil, err
+	}
+
+	return &cipher.StreamReader{S: stream, R: r}, nil
+}
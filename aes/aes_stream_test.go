@@ -0,0 +1,71 @@
+package aes
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"testing/iotest"
+
+	"crypt/blockcipher"
+)
+
+func TestStreamModes(t *testing.T) {
+	key, iv := make([]byte, 16), []byte("0123456789abcdef")
+	plaintext := bytes.Repeat([]byte("stream me "), 50)
+
+	oneShot := map[StreamMode]func(key, iv, plaintext []byte) ([]byte, error){
+		ModeCTR: EncryptCTR,
+		ModeOFB: EncryptOFB,
+		ModeCFB: EncryptCFB,
+	}
+	for mode, encrypt := range oneShot {
+		want, err := encrypt(key, iv, plaintext)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// small writes that never line up with the 16-byte block
+		var buf bytes.Buffer
+		w, err := NewEncryptingWriter(mode, key, iv, &buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for rest := plaintext; len(rest) > 0; {
+			n := min(7, len(rest))
+			if _, err := w.Write(rest[:n]); err != nil {
+				t.Fatal(err)
+			}
+			rest = rest[n:]
+		}
+		if err := w.Close(); err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(buf.Bytes(), want) {
+			t.Errorf("mode %d: streamed ciphertext differs from the one-shot ciphertext", mode)
+		}
+
+		r, err := NewDecryptingReader(mode, key, iv, iotest.OneByteReader(&buf))
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := io.ReadAll(r)
+		if err != nil || !bytes.Equal(got, plaintext) {
+			t.Errorf("mode %d: decrypted %q, %v", mode, got, err)
+		}
+	}
+}
+
+func TestStreamModeErrors(t *testing.T) {
+	key, iv := make([]byte, 16), make([]byte, 16)
+	for _, mode := range []StreamMode{0, blockcipher.CBC, blockcipher.GCM, blockcipher.ECB, 42} {
+		if _, err := NewEncryptingWriter(mode, key, iv, io.Discard); err != ErrUnknownMode {
+			t.Errorf("mode %d: NewEncryptingWriter got %v, want ErrUnknownMode", mode, err)
+		}
+		if _, err := NewDecryptingReader(mode, key, iv, bytes.NewReader(nil)); err != ErrUnknownMode {
+			t.Errorf("mode %d: NewDecryptingReader got %v, want ErrUnknownMode", mode, err)
+		}
+	}
+	if _, err := NewEncryptingWriter(ModeCTR, key, iv[:12], io.Discard); err != IVSizeError(12) {
+		t.Errorf("12-byte IV: got %v, want IVSizeError(12)", err)
+	}
+}
@@ -0,0 +1,167 @@
+package aes
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/subtle"
+	"encoding/binary"
+	"errors"
+	"io"
+)
+
+// ErrXTSSectorSize is returned when a sector is shorter than one AES block or the
+// sector size of an image is not positive.
+var ErrXTSSectorSize = errors.New("aes: XTS sector must be at least one block")
+
+// XTS implements XTS-AES (IEEE 1619) with ciphertext stealing, encrypting each
+// sector in place under a tweak derived from its sector number.
+type XTS struct {
+	k1, k2  cipher.Block
+	keySize KeySize
+}
+
+// NewXTS returns an XTS-AES cipher. The key is 32 or 64 bytes: the first half keys
+// the data, the second half keys the tweak.
+func NewXTS(key []byte) (*XTS, error) {
+	if len(key) != 32 && len(key) != 64 {
+		return nil, KeySizeError(len(key))
+	}
+
+	k1, err := newBlock(key[:len(key)/2])
+	if err != nil {
+		return nil, err
+	}
+	k2, err := newBlock(key[len(key)/2:])
+	if err != nil {
+		return nil, err
+	}
+	return &XTS{k1: k1, k2: k2, keySize: KeySize(len(key) / 2)}, nil
+}
+
+// KeySize reports the AES variant of one key half.
+func (x *XTS) KeySize() KeySize { return x.keySize }
+
+// EncryptSector encrypts one sector of src into dst. The sector may be any length of
+// at least 16 bytes; a trailing partial block is handled with ciphertext stealing.
+// dst and src may overlap entirely.
+func (x *XTS) EncryptSector(dst, src []byte, sectorNum uint64) error {
+	return x.cryptSector(dst, src, sectorNum, false)
+}
+
+// DecryptSector decrypts one sector produced by EncryptSector with the same sector number.
+func (x *XTS) DecryptSector(dst, src []byte, sectorNum uint64) error {
+	return x.cryptSector(dst, src, sectorNum, true)
+}
+
+func (x *XTS) cryptSector(dst, src []byte, sectorNum uint64, decrypt bool) error {
+	if len(src) < aes.BlockSize {
+		return ErrXTSSectorSize
+	}
+	if len(dst) < len(src) {
+		return errors.New("aes: XTS output smaller than input")
+	}
+
+	var tweak [aes.BlockSize]byte
+	binary.LittleEndian.PutUint64(tweak[:8], sectorNum)
+	x.k2.Encrypt(tweak[:], tweak[:])
+
+	crypt := x.k1.Encrypt
+	if decrypt {
+		crypt = x.k1.Decrypt
+	}
+
+	full := len(src) / aes.BlockSize
+	tail := len(src) % aes.BlockSize
+	if tail != 0 {
+		full-- // the last full block takes part in ciphertext stealing
+	}
+
+	for i := 0; i < full; i++ {
+		off := i * aes.BlockSize
+		xtsBlock(crypt, dst[off:off+aes.BlockSize], src[off:off+aes.BlockSize], tweak[:])
+		xtsMulAlpha(tweak[:])
+	}
+	if tail == 0 {
+		return nil
+	}
+
+	// ciphertext stealing (IEEE 1619 section 5.3.2): on decryption the last two
+	// tweaks are used in swapped order
+	off := full * aes.BlockSize
+	first, second := tweak, tweak
+	xtsMulAlpha(second[:])
+	if decrypt {
+		first, second = second, first
+	}
+
+	var cc, pp [aes.BlockSize]byte
+	xtsBlock(crypt, cc[:], src[off:off+aes.BlockSize], first[:])
+	copy(pp[:], src[off+aes.BlockSize:])
+	copy(pp[tail:], cc[tail:])
+
+	copy(dst[off+aes.BlockSize:], cc[:tail])
+	xtsBlock(crypt, dst[off:off+aes.BlockSize], pp[:], second[:])
+
+	return nil
+}
+
+// xtsBlock computes fn(src xor tweak) xor tweak for a single block.
+func xtsBlock(fn func(dst, src []byte), dst, src, tweak []byte) {
+	var buf [aes.BlockSize]byte
+	subtle.XORBytes(buf[:], src, tweak)
+	fn(buf[:], buf[:])
+	subtle.XORBytes(dst, buf[:], tweak)
+}
+
+// xtsMulAlpha multiplies the little-endian tweak by the primitive element alpha.
+func xtsMulAlpha(tweak []byte) {
+	var carry byte
+	for i := range tweak {
+		next := tweak[i] >> 7
+		tweak[i] = tweak[i]<<1 | carry
+		carry = next
+	}
+	tweak[0] ^= byte(subtle.ConstantTimeSelect(int(carry), 0x87, 0))
+}
+
+// EncryptImage encrypts the first size bytes of src into dst sector by sector,
+// using the sector index as the tweak. A final partial sector must still be at
+// least one block long. If src holds fewer than size bytes, io.ErrUnexpectedEOF is
+// returned.
+func (x *XTS) EncryptImage(dst io.WriterAt, src io.ReaderAt, size int64, sectorSize int) error {
+	return x.cryptImage(dst, src, size, sectorSize, false)
+}
+
+// DecryptImage decrypts an image produced by EncryptImage with the same sector size.
+func (x *XTS) DecryptImage(dst io.WriterAt, src io.ReaderAt, size int64, sectorSize int) error {
+	return x.cryptImage(dst, src, size, sectorSize, true)
+}
+
+func (x *XTS) cryptImage(dst io.WriterAt, src io.ReaderAt, size int64, sectorSize int, decrypt bool) error {
+	if sectorSize < aes.BlockSize {
+		return ErrXTSSectorSize
+	}
+
+	sector := make([]byte, sectorSize)
+	for off, num := int64(0), uint64(0); off < size; off, num = off+int64(sectorSize), num+1 {
+		buf := sector
+		if remaining := size - off; remaining < int64(sectorSize) {
+			buf = sector[:remaining]
+		}
+
+		if n, err := src.ReadAt(buf, off); n != len(buf) {
+			if err == nil || err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return err
+		}
+		if err := x.cryptSector(buf, buf, num, decrypt); err != nil {
+			return err
+		}
+		if _, err := dst.WriteAt(buf, off); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
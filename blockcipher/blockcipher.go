@@ -0,0 +1,96 @@
+// Package blockcipher is a registry of block ciphers by name together with the
+// modes of operation that run over them. Each cipher package in this module
+// registers its ciphers when it is imported, and the modes accept any cipher.Block,
+// so a mode is written once and runs over AES, RC5, RC6 and the rest alike.
+package blockcipher
+
+import (
+	"crypto/cipher"
+	"slices"
+	"sort"
+	"strconv"
+	"sync"
+)
+
+// Cipher describes a registered block cipher.
+type Cipher struct {
+	// Name is the name the cipher is registered and looked up under, e.g. "AES".
+	Name string
+
+	// BlockSize is the block size in bytes.
+	BlockSize int
+
+	// KeySizes lists the accepted key lengths in bytes.
+	KeySizes []int
+
+	// New returns the cipher keyed with key.
+	New func(key []byte) (cipher.Block, error)
+}
+
+// ValidKeySize reports whether the cipher accepts keys of n bytes.
+func (c Cipher) ValidKeySize(n int) bool {
+	return slices.Contains(c.KeySizes, n)
+}
+
+var (
+	mu      sync.RWMutex
+	ciphers = make(map[string]Cipher)
+)
+
+// Register makes a block cipher available by name. It panics if the name is empty,
+// the description is incomplete, or a cipher is already registered under the name.
+func Register(c Cipher) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if c.Name == "" || c.New == nil || c.BlockSize <= 0 || len(c.KeySizes) == 0 {
+		panic("blockcipher: Register called with an incomplete cipher " + strconv.Quote(c.Name))
+	}
+	if _, dup := ciphers[c.Name]; dup {
+		panic("blockcipher: Register called twice for cipher " + c.Name)
+	}
+	c.KeySizes = slices.Clone(c.KeySizes)
+	ciphers[c.Name] = c
+}
+
+// Lookup returns the cipher registered under name.
+func Lookup(name string) (Cipher, error) {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	c, ok := ciphers[name]
+	if !ok {
+		return Cipher{}, ErrUnknownCipher
+	}
+	c.KeySizes = slices.Clone(c.KeySizes)
+
+	return c, nil
+}
+
+// Names returns the names of all registered ciphers in sorted order.
+func Names() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	names := make([]string, 0, len(ciphers))
+	for name := range ciphers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// New returns the cipher registered under name keyed with key, after checking the
+// key against the cipher's key sizes.
+func New(name string, key []byte) (cipher.Block, error) {
+	c, err := Lookup(name)
+	if err != nil {
+		return nil, err
+	}
+	if !c.ValidKeySize(len(key)) {
+		return nil, KeySizeError(len(key))
+	}
+
+	return c.New(key)
+}
@@ -0,0 +1,118 @@
+package blockcipher_test
+
+import (
+	"bytes"
+	"crypto/aes"
+	"errors"
+	"slices"
+	"testing"
+
+	_ "crypt/aes"
+	"crypt/blockcipher"
+	"crypt/legacy"
+	_ "crypt/rc"
+)
+
+func TestRegisterLookupNew(t *testing.T) {
+	blockcipher.Register(blockcipher.Cipher{Name: "test-AES", BlockSize: aes.BlockSize, KeySizes: []int{16}, New: aes.NewCipher})
+
+	c, err := blockcipher.Lookup("test-AES")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.BlockSize != aes.BlockSize || !c.ValidKeySize(16) || c.ValidKeySize(32) {
+		t.Errorf("Lookup returned %+v", c)
+	}
+
+	if _, err := blockcipher.New("test-AES", make([]byte, 16)); err != nil {
+		t.Errorf("New: %v", err)
+	}
+	var keyErr blockcipher.KeySizeError
+	if _, err := blockcipher.New("test-AES", make([]byte, 32)); !errors.As(err, &keyErr) || keyErr != 32 {
+		t.Errorf("New with a 32-byte key: got %v, want KeySizeError(32)", err)
+	}
+	if _, err := blockcipher.New("no-such-cipher", nil); err != blockcipher.ErrUnknownCipher {
+		t.Errorf("New with an unknown name: got %v, want ErrUnknownCipher", err)
+	}
+
+	names := blockcipher.Names()
+	if !slices.IsSorted(names) || !slices.Contains(names, "test-AES") {
+		t.Errorf("Names() = %v", names)
+	}
+}
+
+func TestRegisterPanics(t *testing.T) {
+	for name, c := range map[string]blockcipher.Cipher{
+		"duplicate":  {Name: "AES", BlockSize: aes.BlockSize, KeySizes: []int{16}, New: aes.NewCipher},
+		"incomplete": {Name: "test-incomplete", BlockSize: aes.BlockSize},
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s: Register did not panic", name)
+				}
+			}()
+			blockcipher.Register(c)
+		}()
+	}
+}
+
+func TestModesRoundTrip(t *testing.T) {
+	legacy.AllowInsecure = true
+	defer func() { legacy.AllowInsecure = false }()
+
+	plaintext := []byte("every registered cipher runs every mode")
+	modes := map[string]blockcipher.Mode{
+		"CBC": blockcipher.CBC, "CTR": blockcipher.CTR, "GCM": blockcipher.GCM,
+		"CFB": blockcipher.CFB, "OFB": blockcipher.OFB, "ECB": blockcipher.ECB,
+	}
+	for _, name := range blockcipher.Names() {
+		c, err := blockcipher.Lookup(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		block, err := blockcipher.New(name, make([]byte, c.KeySizes[0]))
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+
+		for modeName, mode := range modes {
+			iv := make([]byte, c.BlockSize)
+			if mode == blockcipher.GCM {
+				iv = make([]byte, 12)
+			}
+			ciphertext, err := blockcipher.Encrypt(block, mode, iv, plaintext)
+			if mode == blockcipher.GCM && c.BlockSize != 16 {
+				if err != blockcipher.ErrUnsupportedMode {
+					t.Errorf("%s-%s: got %v, want ErrUnsupportedMode", name, modeName, err)
+				}
+				continue
+			}
+			if err != nil {
+				t.Fatalf("%s-%s: %v", name, modeName, err)
+			}
+			decrypted, err := blockcipher.Decrypt(block, mode, iv, ciphertext)
+			if err != nil || !bytes.Equal(decrypted, plaintext) {
+				t.Errorf("%s-%s: round trip gave %q, %v", name, modeName, decrypted, err)
+			}
+		}
+	}
+}
+
+func TestModeErrors(t *testing.T) {
+	block, err := blockcipher.New("AES", make([]byte, 16))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var ivErr blockcipher.IVSizeError
+	if _, err := blockcipher.Encrypt(block, blockcipher.CBC, make([]byte, 8), nil); !errors.As(err, &ivErr) {
+		t.Errorf("short IV: got %v, want IVSizeError", err)
+	}
+	if _, err := blockcipher.Decrypt(block, blockcipher.CBC, make([]byte, 16), make([]byte, 17)); err != blockcipher.ErrInvalidCiphertext {
+		t.Errorf("partial block: got %v, want ErrInvalidCiphertext", err)
+	}
+	if _, err := blockcipher.Encrypt(block, blockcipher.Mode(0), nil, nil); err != blockcipher.ErrUnsupportedMode {
+		t.Errorf("unknown mode: got %v, want ErrUnsupportedMode", err)
+	}
+}
@@ -0,0 +1,35 @@
+package blockcipher
+
+import (
+	"errors"
+	"strconv"
+)
+
+// KeySizeError is returned by New when a key does not have one of the cipher's key sizes.
+type KeySizeError int
+
+func (k KeySizeError) Error() string {
+	return "blockcipher: invalid key size " + strconv.Itoa(int(k))
+}
+
+// IVSizeError is returned when an IV or nonce does not have the length the mode expects.
+type IVSizeError int
+
+func (i IVSizeError) Error() string {
+	return "blockcipher: invalid IV/nonce size " + strconv.Itoa(int(i))
+}
+
+var (
+	// ErrUnknownCipher is returned when no cipher is registered under a name.
+	ErrUnknownCipher = errors.New("blockcipher: unknown cipher")
+
+	// ErrUnsupportedMode is returned for an unknown Mode, or for GCM with a cipher
+	// whose block is not 128 bits.
+	ErrUnsupportedMode = errors.New("blockcipher: unsupported mode for this cipher")
+
+	// ErrInvalidCiphertext is returned when a ciphertext is empty or is not a multiple of the block size.
+	ErrInvalidCiphertext = errors.New("blockcipher: invalid ciphertext length")
+
+	// ErrAuthenticationFailed is returned when a GCM ciphertext fails authentication.
+	ErrAuthenticationFailed = errors.New("blockcipher: message authentication failed")
+)
@@ -0,0 +1,93 @@
+package blockcipher
+
+import "crypto/cipher"
+
+// Mode selects a mode of operation for Encrypt and Decrypt.
+type Mode int
+
+const (
+	// CBC pads with PKCS#7 and chains blocks; the IV is one block long.
+	CBC Mode = iota + 1
+	// CTR turns the cipher into a stream cipher; the IV is the initial counter block.
+	CTR
+	// GCM authenticates as well as encrypts; the nonce is 12 bytes. It needs a
+	// 128-bit block, so it is not available for 64-bit block ciphers.
+	GCM
+	// CFB is a self-synchronising stream mode; the IV is one block long.
+	CFB
+	// OFB is a synchronous stream mode; the IV is one block long.
+	OFB
+	// ECB pads with PKCS#7 and encrypts every block independently. It leaks
+	// repeated plaintext blocks and is only kept for old data and demonstration.
+	ECB
+)
+
+// gcmBlockSize is the only block size GCM is defined for.
+const gcmBlockSize = 16
+
+// Encrypt encrypts plaintext of any length with b in the given mode.
+func Encrypt(b cipher.Block, mode Mode, iv, plaintext []byte) ([]byte, error) {
+	switch mode {
+	case CBC:
+		return EncryptCBC(b, iv, plaintext, PKCS7)
+	case CTR:
+		return EncryptCTR(b, iv, plaintext)
+	case CFB:
+		return EncryptCFB(b, iv, plaintext)
+	case OFB:
+		return EncryptOFB(b, iv, plaintext)
+	case ECB:
+		return EncryptECB(b, plaintext, PKCS7), nil
+	case GCM:
+		gcm, err := newGCM(b, iv)
+		if err != nil {
+			return nil, err
+		}
+		return gcm.Seal(nil, iv, plaintext, nil), nil
+	default:
+		return nil, ErrUnsupportedMode
+	}
+}
+
+// Decrypt decrypts a ciphertext produced by Encrypt with the same mode.
+func Decrypt(b cipher.Block, mode Mode, iv, ciphertext []byte) ([]byte, error) {
+	switch mode {
+	case CBC:
+		return DecryptCBC(b, iv, ciphertext, PKCS7)
+	case CTR:
+		return DecryptCTR(b, iv, ciphertext)
+	case CFB:
+		return DecryptCFB(b, iv, ciphertext)
+	case OFB:
+		return DecryptOFB(b, iv, ciphertext)
+	case ECB:
+		return DecryptECB(b, ciphertext, PKCS7)
+	case GCM:
+		gcm, err := newGCM(b, iv)
+		if err != nil {
+			return nil, err
+		}
+		plainText, err := gcm.Open(nil, iv, ciphertext, nil)
+		if err != nil {
+			return nil, ErrAuthenticationFailed
+		}
+		return plainText, nil
+	default:
+		return nil, ErrUnsupportedMode
+	}
+}
+
+func newGCM(b cipher.Block, nonce []byte) (cipher.AEAD, error) {
+	if b.BlockSize() != gcmBlockSize {
+		return nil, ErrUnsupportedMode
+	}
+	gcm, err := cipher.NewGCM(b)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkIV(nonce, gcm.NonceSize()); err != nil {
+		return nil, err
+	}
+
+	return gcm, nil
+}
@@ -0,0 +1,176 @@
+package blockcipher
+
+import "crypto/cipher"
+
+func checkIV(iv []byte, size int) error {
+	if len(iv) != size {
+		return IVSizeError(len(iv))
+	}
+
+	return nil
+}
+
+// checkBlocks validates that ciphertext is a non-empty run of whole blocks.
+func checkBlocks(ciphertext []byte, blockSize int) error {
+	if len(ciphertext) == 0 || len(ciphertext)%blockSize != 0 {
+		return ErrInvalidCiphertext
+	}
+
+	return nil
+}
+
+// EncryptCBC pads plaintext and encrypts it in CBC mode with b; the IV is one block long.
+func EncryptCBC(b cipher.Block, iv, plaintext []byte, padding Padding) ([]byte, error) {
+	if err := checkIV(iv, b.BlockSize()); err != nil {
+		return nil, err
+	}
+
+	data := padding.Pad(plaintext, b.BlockSize())
+	mode := cipher.NewCBCEncrypter(b, iv)
+	cipherText := make([]byte, len(data))
+	mode.CryptBlocks(cipherText, data)
+
+	return cipherText, nil
+}
+
+// DecryptCBC decrypts a CBC ciphertext with b and removes the padding.
+func DecryptCBC(b cipher.Block, iv, ciphertext []byte, padding Padding) ([]byte, error) {
+	if err := checkIV(iv, b.BlockSize()); err != nil {
+		return nil, err
+	}
+	if err := checkBlocks(ciphertext, b.BlockSize()); err != nil {
+		return nil, err
+	}
+
+	mode := cipher.NewCBCDecrypter(b, iv)
+	plainText := make([]byte, len(ciphertext))
+	mode.CryptBlocks(plainText, ciphertext)
+
+	return padding.Unpad(plainText, b.BlockSize())
+}
+
+// EncryptCTR encrypts plaintext in CTR mode with b; nonce is the initial counter
+// block and is one block long.
+func EncryptCTR(b cipher.Block, nonce, plaintext []byte) ([]byte, error) {
+	if err := checkIV(nonce, b.BlockSize()); err != nil {
+		return nil, err
+	}
+	ciphertext := make([]byte, len(plaintext))
+	stream := cipher.NewCTR(b, nonce)
+	stream.XORKeyStream(ciphertext, plaintext)
+
+	return ciphertext, nil
+}
+
+// DecryptCTR decrypts a ciphertext produced by EncryptCTR.
+func DecryptCTR(b cipher.Block, nonce, ciphertext []byte) ([]byte, error) {
+	return EncryptCTR(b, nonce, ciphertext) // CTR mode is symmetric
+}
+
+// EncryptCFB encrypts plaintext in CFB mode with b; the IV is one block long.
+func EncryptCFB(b cipher.Block, iv, plaintext []byte) ([]byte, error) {
+	if err := checkIV(iv, b.BlockSize()); err != nil {
+		return nil, err
+	}
+	ciphertext := make([]byte, len(plaintext))
+	stream := cipher.NewCFBEncrypter(b, iv)
+	stream.XORKeyStream(ciphertext, plaintext)
+
+	return ciphertext, nil
+}
+
+// DecryptCFB decrypts a ciphertext produced by EncryptCFB.
+func DecryptCFB(b cipher.Block, iv, ciphertext []byte) ([]byte, error) {
+	if err := checkIV(iv, b.BlockSize()); err != nil {
+		return nil, err
+	}
+	plaintext := make([]byte, len(ciphertext))
+	stream := cipher.NewCFBDecrypter(b, iv)
+	stream.XORKeyStream(plaintext, ciphertext)
+
+	return plaintext, nil
+}
+
+// EncryptOFB encrypts plaintext in OFB mode with b; the IV is one block long.
+func EncryptOFB(b cipher.Block, iv, plaintext []byte) ([]byte, error) {
+	if err := checkIV(iv, b.BlockSize()); err != nil {
+		return nil, err
+	}
+	ciphertext := make([]byte, len(plaintext))
+	stream := cipher.NewOFB(b, iv)
+	stream.XORKeyStream(ciphertext, plaintext)
+
+	return ciphertext, nil
+}
+
+// DecryptOFB decrypts a ciphertext produced by EncryptOFB.
+func DecryptOFB(b cipher.Block, iv, ciphertext []byte) ([]byte, error) {
+	return EncryptOFB(b, iv, ciphertext) // OFB uses same function for encryption/decryption
+}
+
+// EncryptECB pads data and encrypts every block with b independently.
+// ECB leaks repeated plaintext blocks and is only kept for demonstration.
+func EncryptECB(b cipher.Block, data []byte, padding Padding) []byte {
+	paddedData := padding.Pad(data, b.BlockSize())
+	cipherText := make([]byte, len(paddedData))
+	NewECBEncrypter(b).CryptBlocks(cipherText, paddedData)
+
+	return cipherText
+}
+
+// DecryptECB decrypts an ECB ciphertext with b and removes the padding.
+func DecryptECB(b cipher.Block, cipherText []byte, padding Padding) ([]byte, error) {
+	if err := checkBlocks(cipherText, b.BlockSize()); err != nil {
+		return nil, err
+	}
+
+	plainText := make([]byte, len(cipherText))
+	NewECBDecrypter(b).CryptBlocks(plainText, cipherText)
+
+	return padding.Unpad(plainText, b.BlockSize())
+}
+
+type ecbEncrypter struct {
+	b cipher.Block
+}
+
+type ecbDecrypter struct {
+	b cipher.Block
+}
+
+// NewECBEncrypter returns a cipher.BlockMode that encrypts each block of b independently.
+func NewECBEncrypter(b cipher.Block) cipher.BlockMode {
+	return ecbEncrypter{b: b}
+}
+
+// NewECBDecrypter returns a cipher.BlockMode that decrypts each block of b independently.
+func NewECBDecrypter(b cipher.Block) cipher.BlockMode {
+	return ecbDecrypter{b: b}
+}
+
+func (x ecbEncrypter) BlockSize() int { return x.b.BlockSize() }
+
+func (x ecbEncrypter) CryptBlocks(dst, src []byte) {
+	ecbCryptBlocks(x.b.BlockSize(), dst, src, x.b.Encrypt)
+}
+
+func (x ecbDecrypter) BlockSize() int { return x.b.BlockSize() }
+
+func (x ecbDecrypter) CryptBlocks(dst, src []byte) {
+	ecbCryptBlocks(x.b.BlockSize(), dst, src, x.b.Decrypt)
+}
+
+// ecbCryptBlocks applies fn to every block of src, panicking on misuse like the
+// crypto/cipher block modes do.
+func ecbCryptBlocks(blockSize int, dst, src []byte, fn func(dst, src []byte)) {
+	if len(src)%blockSize != 0 {
+		panic("blockcipher: ECB input not full blocks")
+	}
+	if len(dst) < len(src) {
+		panic("blockcipher: ECB output smaller than input")
+	}
+
+	for i := 0; i < len(src); i += blockSize {
+		fn(dst[i:i+blockSize], src[i:i+blockSize])
+	}
+}
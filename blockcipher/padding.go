@@ -0,0 +1,166 @@
+package blockcipher
+
+import (
+	"crypto/subtle"
+	"errors"
+)
+
+// ErrInvalidPadding is returned for any malformed padding. It deliberately carries
+// no detail about what was wrong so that it cannot be used as a padding oracle.
+var ErrInvalidPadding = errors.New("blockcipher: invalid padding")
+
+// Padding is a block padding scheme used by the ECB and CBC modes.
+type Padding interface {
+	// Pad returns a copy of data extended to a multiple of blockSize.
+	Pad(data []byte, blockSize int) []byte
+	// Unpad strips the padding from data, checking it in constant time.
+	Unpad(data []byte, blockSize int) ([]byte, error)
+}
+
+var (
+	// PKCS7 pads with n bytes of value n (RFC 5652). It is the default for the block modes.
+	PKCS7 Padding = pkcs7{}
+	// ANSIX923 pads with zero bytes followed by a final length byte.
+	ANSIX923 Padding = ansiX923{}
+	// ISO7816 pads with a single 0x80 byte followed by zero bytes (ISO/IEC 7816-4).
+	ISO7816 Padding = iso7816{}
+	// ZeroPadding pads with zero bytes up to the block boundary. It cannot round-trip
+	// data that ends in zero bytes and should only be used for interoperability.
+	ZeroPadding Padding = zeroPadding{}
+)
+
+// padTo copies data into a new slice extended by padLen bytes, returning the slice
+// and the padding area for the caller to fill.
+func padTo(data []byte, padLen int) ([]byte, []byte) {
+	padded := make([]byte, len(data)+padLen)
+	copy(padded, data)
+
+	return padded, padded[len(data):]
+}
+
+// lastBlock returns the final block of data, or nil if data is not whole blocks.
+func lastBlock(data []byte, blockSize int) []byte {
+	if blockSize <= 0 || blockSize > 255 || len(data) == 0 || len(data)%blockSize != 0 {
+		return nil
+	}
+
+	return data[len(data)-blockSize:]
+}
+
+type pkcs7 struct{}
+
+func (pkcs7) Pad(data []byte, blockSize int) []byte {
+	padding := blockSize - len(data)%blockSize
+	padded, pad := padTo(data, padding)
+	for i := range pad {
+		pad[i] = byte(padding)
+	}
+
+	return padded
+}
+
+func (pkcs7) Unpad(data []byte, blockSize int) ([]byte, error) {
+	last := lastBlock(data, blockSize)
+	if last == nil {
+		return nil, ErrInvalidPadding
+	}
+
+	padding := int(last[blockSize-1])
+	good := subtle.ConstantTimeLessOrEq(1, padding) & subtle.ConstantTimeLessOrEq(padding, blockSize)
+	for i, b := range last {
+		inPad := subtle.ConstantTimeLessOrEq(blockSize-i, padding)
+		good &= subtle.ConstantTimeSelect(inPad, subtle.ConstantTimeByteEq(b, byte(padding)), 1)
+	}
+	if good != 1 {
+		return nil, ErrInvalidPadding
+	}
+
+	return data[:len(data)-padding], nil
+}
+
+type ansiX923 struct{}
+
+func (ansiX923) Pad(data []byte, blockSize int) []byte {
+	padding := blockSize - len(data)%blockSize
+	padded, pad := padTo(data, padding)
+	pad[padding-1] = byte(padding)
+
+	return padded
+}
+
+func (ansiX923) Unpad(data []byte, blockSize int) ([]byte, error) {
+	last := lastBlock(data, blockSize)
+	if last == nil {
+		return nil, ErrInvalidPadding
+	}
+
+	padding := int(last[blockSize-1])
+	good := subtle.ConstantTimeLessOrEq(1, padding) & subtle.ConstantTimeLessOrEq(padding, blockSize)
+	for i, b := range last[:blockSize-1] {
+		inPad := subtle.ConstantTimeLessOrEq(blockSize-i, padding)
+		good &= subtle.ConstantTimeSelect(inPad, subtle.ConstantTimeByteEq(b, 0), 1)
+	}
+	if good != 1 {
+		return nil, ErrInvalidPadding
+	}
+
+	return data[:len(data)-padding], nil
+}
+
+type iso7816 struct{}
+
+func (iso7816) Pad(data []byte, blockSize int) []byte {
+	padding := blockSize - len(data)%blockSize
+	padded, pad := padTo(data, padding)
+	pad[0] = 0x80
+
+	return padded
+}
+
+func (iso7816) Unpad(data []byte, blockSize int) ([]byte, error) {
+	last := lastBlock(data, blockSize)
+	if last == nil {
+		return nil, ErrInvalidPadding
+	}
+
+	// find the last non-zero byte without branching on the data
+	pos, marker, found := 0, 0, 0
+	for i, b := range last {
+		nonZero := 1 ^ subtle.ConstantTimeByteEq(b, 0)
+		pos = subtle.ConstantTimeSelect(nonZero, i, pos)
+		marker = subtle.ConstantTimeSelect(nonZero, int(b), marker)
+		found |= nonZero
+	}
+	if found&subtle.ConstantTimeByteEq(byte(marker), 0x80) != 1 {
+		return nil, ErrInvalidPadding
+	}
+
+	return data[:len(data)-blockSize+pos], nil
+}
+
+type zeroPadding struct{}
+
+func (zeroPadding) Pad(data []byte, blockSize int) []byte {
+	padding := (blockSize - len(data)%blockSize) % blockSize
+	if len(data) == 0 {
+		padding = blockSize // always produce at least one block
+	}
+	padded, _ := padTo(data, padding)
+
+	return padded
+}
+
+func (zeroPadding) Unpad(data []byte, blockSize int) ([]byte, error) {
+	last := lastBlock(data, blockSize)
+	if last == nil {
+		return nil, ErrInvalidPadding
+	}
+
+	padding, trailing := 0, 1
+	for i := blockSize - 1; i >= 0; i-- {
+		trailing &= subtle.ConstantTimeByteEq(last[i], 0)
+		padding += trailing
+	}
+
+	return data[:len(data)-padding], nil
+}
@@ -0,0 +1,74 @@
+package blockcipher_test
+
+import (
+	"bytes"
+	"testing"
+
+	"crypt/blockcipher"
+)
+
+var paddings = map[string]blockcipher.Padding{
+	"PKCS7":    blockcipher.PKCS7,
+	"ANSIX923": blockcipher.ANSIX923,
+	"ISO7816":  blockcipher.ISO7816,
+	"Zero":     blockcipher.ZeroPadding,
+}
+
+func TestPaddingRoundTrip(t *testing.T) {
+	for _, blockSize := range []int{8, 16} {
+		for name, p := range paddings {
+			for n := 0; n <= 2*blockSize; n++ {
+				// non-zero data so that ZeroPadding can round-trip it
+				data := bytes.Repeat([]byte{0xa5}, n)
+				padded := p.Pad(data, blockSize)
+				if len(padded) == 0 || len(padded)%blockSize != 0 || len(padded) < n {
+					t.Fatalf("%s, block %d, %d bytes: padded to %d bytes", name, blockSize, n, len(padded))
+				}
+				if !bytes.Equal(padded[:n], data) {
+					t.Fatalf("%s, block %d, %d bytes: Pad changed the data", name, blockSize, n)
+				}
+				got, err := p.Unpad(padded, blockSize)
+				if err != nil || !bytes.Equal(got, data) {
+					t.Errorf("%s, block %d, %d bytes: Unpad = %x, %v", name, blockSize, n, got, err)
+				}
+			}
+		}
+	}
+}
+
+func TestPaddingRejects(t *testing.T) {
+	block := func(tail ...byte) []byte {
+		return append(bytes.Repeat([]byte{'a'}, 16-len(tail)), tail...)
+	}
+	cases := []struct {
+		name    string
+		padding blockcipher.Padding
+		data    []byte
+	}{
+		{"PKCS7 pad byte 0", blockcipher.PKCS7, block(0)},
+		{"PKCS7 pad byte 17", blockcipher.PKCS7, block(17)},
+		{"PKCS7 inconsistent bytes", blockcipher.PKCS7, block(1, 2, 3)},
+		{"ANSIX923 pad byte 0", blockcipher.ANSIX923, block(0)},
+		{"ANSIX923 pad byte 17", blockcipher.ANSIX923, block(17)},
+		{"ANSIX923 pad byte 255", blockcipher.ANSIX923, block(255)},
+		{"ANSIX923 non-zero filler", blockcipher.ANSIX923, block(0, 1, 0, 4)},
+		{"ANSIX923 full block non-zero filler", blockcipher.ANSIX923, append(bytes.Repeat([]byte{0}, 14), 1, 16)},
+		{"ISO7816 all zeros", blockcipher.ISO7816, make([]byte, 16)},
+		{"ISO7816 no marker", blockcipher.ISO7816, block(0x7f, 0, 0)},
+		{"ISO7816 data after marker", blockcipher.ISO7816, block(0x80, 0, 1)},
+	}
+	for _, c := range cases {
+		if _, err := c.padding.Unpad(c.data, 16); err != blockcipher.ErrInvalidPadding {
+			t.Errorf("%s: got %v, want ErrInvalidPadding", c.name, err)
+		}
+	}
+
+	// input that is not whole blocks is rejected by every scheme
+	for name, p := range paddings {
+		for _, n := range []int{0, 1, 15, 17} {
+			if _, err := p.Unpad(make([]byte, n), 16); err != blockcipher.ErrInvalidPadding {
+				t.Errorf("%s, %d bytes: got %v, want ErrInvalidPadding", name, n, err)
+			}
+		}
+	}
+}
@@ -1,27 +1,26 @@
 package chacha20salsa20
 
 import (
-	"crypto/rand"
 	"fmt"
 
+	"crypt/drbg"
+
 	"golang.org/x/crypto/chacha20"
 )
 
 func ChaCha20() {
-	key := make([]byte, 32)
-	_, err := rand.Read(key)
+	key, err := drbg.Read(nil, 32)
 	if err != nil {
 		panic(err)
 	}
 
-	nounce := make([]byte, 12)
-	_, err = rand.Read(nounce)
+	nounce, err := drbg.Read(nil, 12)
 	if err != nil {
 		panic(err)
 	}
 
 	cipherText := encryptChaCha20([]byte("This is a secret message from mustafa!!"), key, nounce) // Encrypt
-	fmt.Println(string(decryptChaCha20(cipherText, key, nounce))) // Decrypt
+	fmt.Println(string(decryptChaCha20(cipherText, key, nounce)))                                 // Decrypt
 }
 
 func encryptChaCha20(message, key, nounce []byte) []byte {
@@ -0,0 +1,71 @@
+package chacha20salsa20
+
+import (
+	"errors"
+	"io"
+
+	"golang.org/x/crypto/chacha20"
+)
+
+const (
+	chacha20BlockSize = 64
+	// chacha20MaxOffset is the length of the keystream addressable by the 32-bit block counter.
+	chacha20MaxOffset = 1 << 32 * chacha20BlockSize
+)
+
+// ErrOffsetOutOfRange is returned when a position lies beyond the 256 GiB keystream of one key and nonce.
+var ErrOffsetOutOfRange = errors.New("chacha20salsa20: offset beyond the ChaCha20 keystream")
+
+// NewChaCha20At returns an unauthenticated ChaCha20 cipher whose keystream starts at
+// byte offset, so encrypting or decrypting with it gives the same bytes as starting
+// at zero and discarding the first offset bytes. The block counter is set to offset/64
+// and the remaining offset%64 bytes of that block are skipped.
+func NewChaCha20At(key, nonce []byte, offset uint64) (*chacha20.Cipher, error) {
+	if offset >= chacha20MaxOffset {
+		return nil, ErrOffsetOutOfRange
+	}
+
+	chaChaCipher, err := chacha20.NewUnauthenticatedCipher(key, nonce)
+	if err != nil {
+		return nil, err
+	}
+	chaChaCipher.SetCounter(uint32(offset / chacha20BlockSize))
+
+	skip := make([]byte, offset%chacha20BlockSize)
+	chaChaCipher.XORKeyStream(skip, skip)
+
+	return chaChaCipher, nil
+}
+
+type decryptingReaderAt struct {
+	key, nonce []byte
+	r          io.ReaderAt
+}
+
+// NewDecryptingReaderAt returns an io.ReaderAt that decrypts any range of a ChaCha20
+// ciphertext stored in r without processing the bytes before it. The ciphertext is
+// unauthenticated, so callers must verify its integrity separately.
+func NewDecryptingReaderAt(key, nonce []byte, r io.ReaderAt) (io.ReaderAt, error) {
+	if _, err := chacha20.NewUnauthenticatedCipher(key, nonce); err != nil {
+		return nil, err
+	}
+
+	return &decryptingReaderAt{key: key, nonce: nonce, r: r}, nil
+}
+
+func (d *decryptingReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	if off < 0 || uint64(off)+uint64(len(p)) > chacha20MaxOffset {
+		return 0, ErrOffsetOutOfRange
+	}
+
+	n, err := d.r.ReadAt(p, off)
+	if n > 0 {
+		chaChaCipher, cipherErr := NewChaCha20At(d.key, d.nonce, uint64(off))
+		if cipherErr != nil {
+			return 0, cipherErr
+		}
+		chaChaCipher.XORKeyStream(p[:n], p[:n])
+	}
+
+	return n, err
+}
@@ -0,0 +1,100 @@
+package chacha20salsa20
+
+import (
+	"bytes"
+	"testing"
+
+	"golang.org/x/crypto/chacha20"
+)
+
+// zeroReaderAt is a ciphertext of zero bytes at every offset, so decrypting it
+// yields the keystream.
+type zeroReaderAt struct{}
+
+func (zeroReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	clear(p)
+	return len(p), nil
+}
+
+func seekKeyNonce() (key, nonce []byte) {
+	return bytes.Repeat([]byte{0x42}, chacha20.KeySize), bytes.Repeat([]byte{0x24}, chacha20.NonceSize)
+}
+
+func TestChaCha20At(t *testing.T) {
+	key, nonce := seekKeyNonce()
+	sequential, err := chacha20.NewUnauthenticatedCipher(key, nonce)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keystream := make([]byte, 4*chacha20BlockSize)
+	sequential.XORKeyStream(keystream, keystream)
+
+	for _, off := range []uint64{0, 1, 63, 64, 65, 130} {
+		c, err := NewChaCha20At(key, nonce, off)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := make([]byte, 100)
+		c.XORKeyStream(got, got)
+		if want := keystream[off : off+100]; !bytes.Equal(got, want) {
+			t.Errorf("offset %d: got %x, want %x", off, got, want)
+		}
+	}
+
+	r, err := NewDecryptingReaderAt(key, nonce, zeroReaderAt{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// a read that starts and ends inside blocks
+	got := make([]byte, 90)
+	if n, err := r.ReadAt(got, 37); n != len(got) || err != nil {
+		t.Fatalf("ReadAt = %d, %v", n, err)
+	}
+	if !bytes.Equal(got, keystream[37:127]) {
+		t.Errorf("ReadAt(37): got %x, want %x", got, keystream[37:127])
+	}
+}
+
+func TestChaCha20AtLimit(t *testing.T) {
+	key, nonce := seekKeyNonce()
+
+	// the last keystream byte is byte 63 of block 2^32-1
+	last, err := chacha20.NewUnauthenticatedCipher(key, nonce)
+	if err != nil {
+		t.Fatal(err)
+	}
+	last.SetCounter(1<<32 - 1)
+	block := make([]byte, chacha20BlockSize)
+	last.XORKeyStream(block, block)
+
+	c, err := NewChaCha20At(key, nonce, chacha20MaxOffset-1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := make([]byte, 1)
+	c.XORKeyStream(got, got)
+	if got[0] != block[63] {
+		t.Errorf("last keystream byte: got %x, want %x", got[0], block[63])
+	}
+	for _, off := range []uint64{chacha20MaxOffset, chacha20MaxOffset + 1, 1<<64 - 1} {
+		if _, err := NewChaCha20At(key, nonce, off); err != ErrOffsetOutOfRange {
+			t.Errorf("offset %d: got %v, want ErrOffsetOutOfRange", off, err)
+		}
+	}
+
+	r, err := NewDecryptingReaderAt(key, nonce, zeroReaderAt{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n, err := r.ReadAt(got, chacha20MaxOffset-1); n != 1 || err != nil || got[0] != block[63] {
+		t.Errorf("ReadAt of the last byte = %d, %v, %x", n, err, got)
+	}
+	for _, c := range []struct {
+		off int64
+		len int
+	}{{chacha20MaxOffset - 1, 2}, {chacha20MaxOffset, 1}, {-1, 1}} {
+		if _, err := r.ReadAt(make([]byte, c.len), c.off); err != ErrOffsetOutOfRange {
+			t.Errorf("ReadAt(%d bytes at %d): got %v, want ErrOffsetOutOfRange", c.len, c.off, err)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package chacha20salsa20
+
+import (
+	"crypto/cipher"
+	"io"
+
+	"crypt/drbg"
+
+	"golang.org/x/crypto/chacha20poly1305"
+)
+
+// SealChaCha20Poly1305 encrypts and authenticates plaintext with ChaCha20-Poly1305
+// (RFC 8439) under a fresh 12-byte nonce and returns nonce || ciphertext || tag.
+// The nonce is read from rand. Random 12-byte
+// nonces should not be used for more than about 2^32 messages per key; prefer
+// SealXChaCha20Poly1305 for high volumes.
+func SealChaCha20Poly1305(rand io.Reader, key, plaintext, additionalData []byte) ([]byte, error) {
+	aead, err := chacha20poly1305.New(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return seal(rand, aead, plaintext, additionalData)
+}
+
+// OpenChaCha20Poly1305 opens an envelope produced by SealChaCha20Poly1305.
+func OpenChaCha20Poly1305(key, envelope, additionalData []byte) ([]byte, error) {
+	aead, err := chacha20poly1305.New(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return open(aead, envelope, additionalData)
+}
+
+// SealXChaCha20Poly1305 is like SealChaCha20Poly1305 but uses XChaCha20-Poly1305
+// with a 24-byte nonce, which is safe to choose at random for any number of messages.
+func SealXChaCha20Poly1305(rand io.Reader, key, plaintext, additionalData []byte) ([]byte, error) {
+	aead, err := chacha20poly1305.NewX(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return seal(rand, aead, plaintext, additionalData)
+}
+
+// OpenXChaCha20Poly1305 opens an envelope produced by SealXChaCha20Poly1305.
+func OpenXChaCha20Poly1305(key, envelope, additionalData []byte) ([]byte, error) {
+	aead, err := chacha20poly1305.NewX(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return open(aead, envelope, additionalData)
+}
+
+func seal(rand io.Reader, aead cipher.AEAD, plaintext, additionalData []byte) ([]byte, error) {
+	nonce, err := drbg.Read(rand, aead.NonceSize())
+	if err != nil {
+		return nil, err
+	}
+
+	envelope := make([]byte, 0, len(nonce)+len(plaintext)+aead.Overhead())
+	envelope = append(envelope, nonce...)
+	return aead.Seal(envelope, nonce, plaintext, additionalData), nil
+}
+
+func open(aead cipher.AEAD, envelope, additionalData []byte) ([]byte, error) {
+	if len(envelope) < aead.NonceSize()+aead.Overhead() {
+		return nil, ErrInvalidEnvelope
+	}
+
+	nonce := envelope[:aead.NonceSize()]
+	plaintext, err := aead.Open(nil, nonce, envelope[aead.NonceSize():], additionalData)
+	if err != nil {
+		return nil, ErrAuthenticationFailed
+	}
+	return plaintext, nil
+}
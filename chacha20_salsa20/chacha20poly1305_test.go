@@ -0,0 +1,59 @@
+package chacha20salsa20
+
+import (
+	"bytes"
+	"io"
+	"testing"
+)
+
+func TestChaCha20Poly1305Envelopes(t *testing.T) {
+	variants := []struct {
+		name      string
+		nonceSize int
+		seal      func(rand io.Reader, key, plaintext, additionalData []byte) ([]byte, error)
+		open      func(key, envelope, additionalData []byte) ([]byte, error)
+	}{
+		{"ChaCha20-Poly1305", 12, SealChaCha20Poly1305, OpenChaCha20Poly1305},
+		{"XChaCha20-Poly1305", 24, SealXChaCha20Poly1305, OpenXChaCha20Poly1305},
+	}
+	key := bytes.Repeat([]byte{7}, 32)
+	plaintext := []byte("attack at dawn")
+	ad := []byte("header")
+
+	for _, v := range variants {
+		nonce := bytes.Repeat([]byte{0xee}, v.nonceSize)
+		envelope, err := v.seal(bytes.NewReader(nonce), key, plaintext, ad)
+		if err != nil {
+			t.Fatalf("%s: %v", v.name, err)
+		}
+		if len(envelope) != v.nonceSize+len(plaintext)+16 || !bytes.Equal(envelope[:v.nonceSize], nonce) {
+			t.Errorf("%s: envelope does not start with the %d-byte nonce", v.name, v.nonceSize)
+		}
+		if got, err := v.open(key, envelope, ad); err != nil || !bytes.Equal(got, plaintext) {
+			t.Errorf("%s: open = %q, %v", v.name, got, err)
+		}
+
+		if _, err := v.open(key, envelope, []byte("other")); err != ErrAuthenticationFailed {
+			t.Errorf("%s, wrong AAD: got %v, want ErrAuthenticationFailed", v.name, err)
+		}
+		for _, i := range []int{0, v.nonceSize, len(envelope) - 1} {
+			tampered := bytes.Clone(envelope)
+			tampered[i] ^= 1
+			if _, err := v.open(key, tampered, ad); err != ErrAuthenticationFailed {
+				t.Errorf("%s, flipped byte %d: got %v, want ErrAuthenticationFailed", v.name, i, err)
+			}
+		}
+		if _, err := v.open(key, envelope[:len(envelope)-1], ad); err != ErrAuthenticationFailed {
+			t.Errorf("%s, one byte short: got %v, want ErrAuthenticationFailed", v.name, err)
+		}
+		for _, n := range []int{0, v.nonceSize, v.nonceSize + 15} {
+			if _, err := v.open(key, envelope[:n], ad); err != ErrInvalidEnvelope {
+				t.Errorf("%s, %d bytes: got %v, want ErrInvalidEnvelope", v.name, n, err)
+			}
+		}
+
+		if _, err := v.seal(nil, key[:16], plaintext, ad); err == nil {
+			t.Errorf("%s: 16-byte key accepted", v.name)
+		}
+	}
+}
@@ -0,0 +1,22 @@
+package chacha20salsa20
+
+import (
+	"errors"
+
+	"crypt/aeadstream"
+)
+
+var (
+	// ErrInvalidKeySize is returned when a key does not have the length the construction requires.
+	ErrInvalidKeySize = errors.New("chacha20salsa20: invalid key size")
+
+	// ErrInvalidEnvelope is returned when a sealed envelope is too short to hold its nonce and tag.
+	ErrInvalidEnvelope = errors.New("chacha20salsa20: invalid envelope")
+
+	// ErrInvalidStream is returned when an encrypted stream header is missing or malformed,
+	// or when a stream would need more segments than the counter can address.
+	ErrInvalidStream = aeadstream.ErrInvalidStream
+
+	// ErrAuthenticationFailed is returned when a ciphertext or its associated data has been modified.
+	ErrAuthenticationFailed = errors.New("chacha20salsa20: message authentication failed")
+)
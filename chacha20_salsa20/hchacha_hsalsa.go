@@ -0,0 +1,63 @@
+package chacha20salsa20
+
+import (
+	"errors"
+
+	"golang.org/x/crypto/chacha20"
+	"golang.org/x/crypto/salsa20"
+	"golang.org/x/crypto/salsa20/salsa"
+)
+
+// ErrInvalidNonceSize is returned when a nonce does not have the length the construction requires.
+var ErrInvalidNonceSize = errors.New("chacha20salsa20: invalid nonce size")
+
+// HChaCha20 derives a 32-byte subkey from a 32-byte key and a 16-byte nonce, as used
+// by XChaCha20 to extend the nonce to 24 bytes (draft-irtf-cfrg-xchacha section 2.2).
+func HChaCha20(key, nonce []byte) ([]byte, error) {
+	if len(key) != chacha20.KeySize {
+		return nil, ErrInvalidKeySize
+	}
+	if len(nonce) != 16 {
+		return nil, ErrInvalidNonceSize
+	}
+
+	return chacha20.HChaCha20(key, nonce)
+}
+
+// HSalsa20 derives a 32-byte subkey from a 32-byte key and a 16-byte nonce, as used
+// by XSalsa20 and by NaCl box to turn a shared secret into a key.
+func HSalsa20(key, nonce []byte) ([]byte, error) {
+	if len(key) != 32 {
+		return nil, ErrInvalidKeySize
+	}
+	if len(nonce) != 16 {
+		return nil, ErrInvalidNonceSize
+	}
+
+	var subkey [32]byte
+	salsa.HSalsa20(&subkey, (*[16]byte)(nonce), (*[32]byte)(key), &salsa.Sigma)
+	return subkey[:], nil
+}
+
+// XSalsa20 XORs src with the XSalsa20 keystream into dst. XSalsa20 takes a 24-byte
+// nonce: the first 16 bytes and the key go through HSalsa20 to give a subkey, which
+// then drives Salsa20 with the last 8 bytes, so random nonces are safe to use.
+func XSalsa20(dst, src, key, nonce []byte) error {
+	if len(key) != 32 {
+		return ErrInvalidKeySize
+	}
+	if len(nonce) != 24 {
+		return ErrInvalidNonceSize
+	}
+	if len(dst) < len(src) {
+		return errors.New("chacha20salsa20: output smaller than input")
+	}
+
+	subkey, err := HSalsa20(key, nonce[:16])
+	if err != nil {
+		return err
+	}
+	salsa20.XORKeyStream(dst[:len(src)], src, nonce[16:], (*[32]byte)(subkey))
+
+	return nil
+}
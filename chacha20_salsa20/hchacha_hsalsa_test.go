@@ -0,0 +1,75 @@
+package chacha20salsa20
+
+import (
+	"bytes"
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+func decodeHex(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(strings.ReplaceAll(s, " ", ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+// TestHChaCha20Vector is the test vector of draft-irtf-cfrg-xchacha section 2.2.1.
+func TestHChaCha20Vector(t *testing.T) {
+	key := decodeHex(t, "00010203 04050607 08090a0b 0c0d0e0f 10111213 14151617 18191a1b 1c1d1e1f")
+	nonce := decodeHex(t, "00000009 0000004a 00000000 31415927")
+	want := decodeHex(t, "82413b42 27b27bfe d30e4250 8a877d73 a0f9e4d5 8a74a853 c12ec413 26d3ecdc")
+
+	got, err := HChaCha20(key, nonce)
+	if err != nil || !bytes.Equal(got, want) {
+		t.Errorf("got %x, %v, want %x", got, err, want)
+	}
+}
+
+// TestHSalsa20Vectors derive the "firstkey" and "secondkey" of the NaCl test suite:
+// the box key of the Alice and Bob example and the XSalsa20 subkey of its nonce.
+func TestHSalsa20Vectors(t *testing.T) {
+	shared := decodeHex(t, "4a5d9d5b a4ce2de1 728e3bf4 80350f25 e07e21c9 47d19e33 76f09b3c 1e161742")
+	firstKey := decodeHex(t, "1b275564 73e985d4 62cd5119 7a9a46c7 6009549e ac6474f2 06c4ee08 44f68389")
+	secondKey := decodeHex(t, "dc908dda 0b9344a9 53629b73 38207788 80f3ceb4 21bb61b9 1cbd4c3e 66256ce4")
+
+	got, err := HSalsa20(shared, make([]byte, 16))
+	if err != nil || !bytes.Equal(got, firstKey) {
+		t.Errorf("firstkey: got %x, %v, want %x", got, err, firstKey)
+	}
+	got, err = HSalsa20(firstKey, decodeHex(t, "69696ee9 55b62b73 cd62bda8 75fc73d6"))
+	if err != nil || !bytes.Equal(got, secondKey) {
+		t.Errorf("secondkey: got %x, %v, want %x", got, err, secondKey)
+	}
+}
+
+// TestXSalsa20Vector is the known-answer test of golang.org/x/crypto/salsa20.
+func TestXSalsa20Vector(t *testing.T) {
+	key := []byte("this is 32-byte key for xsalsa20")
+	nonce := []byte("24-byte nonce for xsalsa")
+	plaintext := []byte("Hello world!")
+	want := decodeHex(t, "002d4513 843fc240 c401e541")
+
+	got := make([]byte, len(plaintext))
+	if err := XSalsa20(got, plaintext, key, nonce); err != nil || !bytes.Equal(got, want) {
+		t.Errorf("got %x, %v, want %x", got, err, want)
+	}
+	if err := XSalsa20(got, got, key, nonce); err != nil || !bytes.Equal(got, plaintext) {
+		t.Errorf("decryption: got %q, %v", got, err)
+	}
+}
+
+func TestSubkeyErrors(t *testing.T) {
+	key := make([]byte, 32)
+	if _, err := HChaCha20(key[:16], make([]byte, 16)); err != ErrInvalidKeySize {
+		t.Errorf("HChaCha20 16-byte key: got %v", err)
+	}
+	if _, err := HSalsa20(key, make([]byte, 24)); err != ErrInvalidNonceSize {
+		t.Errorf("HSalsa20 24-byte nonce: got %v", err)
+	}
+	if err := XSalsa20(make([]byte, 4), make([]byte, 4), key, make([]byte, 16)); err != ErrInvalidNonceSize {
+		t.Errorf("XSalsa20 16-byte nonce: got %v", err)
+	}
+}
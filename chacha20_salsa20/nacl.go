@@ -0,0 +1,95 @@
+package chacha20salsa20
+
+import (
+	"io"
+
+	"crypt/drbg"
+
+	"golang.org/x/crypto/nacl/box"
+	"golang.org/x/crypto/nacl/secretbox"
+)
+
+// naclNonceSize is the XSalsa20 nonce size shared by secretbox and box.
+const naclNonceSize = 24
+
+// SealSecretbox encrypts and authenticates message with NaCl secretbox
+// (XSalsa20-Poly1305) under a 32-byte key and returns nonce(24) || box, the layout
+// libsodium users commonly store. The nonce is read from rand.
+func SealSecretbox(rand io.Reader, key, message []byte) ([]byte, error) {
+	if len(key) != 32 {
+		return nil, ErrInvalidKeySize
+	}
+	nonce, err := drbg.Read(rand, naclNonceSize)
+	if err != nil {
+		return nil, err
+	}
+
+	return secretbox.Seal(nonce, message, (*[naclNonceSize]byte)(nonce), (*[32]byte)(key)), nil
+}
+
+// OpenSecretbox opens an envelope produced by SealSecretbox.
+func OpenSecretbox(key, envelope []byte) ([]byte, error) {
+	if len(key) != 32 {
+		return nil, ErrInvalidKeySize
+	}
+	if len(envelope) < naclNonceSize+secretbox.Overhead {
+		return nil, ErrInvalidEnvelope
+	}
+
+	message, ok := secretbox.Open(nil, envelope[naclNonceSize:], (*[naclNonceSize]byte)(envelope), (*[32]byte)(key))
+	if !ok {
+		return nil, ErrAuthenticationFailed
+	}
+	return message, nil
+}
+
+// GenerateBoxKeyPair generates a Curve25519 key pair for box from rand.
+func GenerateBoxKeyPair(rand io.Reader) (publicKey, privateKey *[32]byte, err error) {
+	return box.GenerateKey(drbg.Default(rand))
+}
+
+// SealBox encrypts and authenticates message from the holder of privateKey to the
+// holder of peersPublicKey with NaCl box (Curve25519, XSalsa20-Poly1305), returning
+// nonce(24) || box. The nonce is read from rand.
+func SealBox(rand io.Reader, message []byte, peersPublicKey, privateKey *[32]byte) ([]byte, error) {
+	nonce, err := drbg.Read(rand, naclNonceSize)
+	if err != nil {
+		return nil, err
+	}
+
+	return box.Seal(nonce, message, (*[naclNonceSize]byte)(nonce), peersPublicKey, privateKey), nil
+}
+
+// OpenBox opens an envelope produced by SealBox, using the sender's public key and
+// the recipient's private key.
+func OpenBox(envelope []byte, peersPublicKey, privateKey *[32]byte) ([]byte, error) {
+	if len(envelope) < naclNonceSize+box.Overhead {
+		return nil, ErrInvalidEnvelope
+	}
+
+	message, ok := box.Open(nil, envelope[naclNonceSize:], (*[naclNonceSize]byte)(envelope), peersPublicKey, privateKey)
+	if !ok {
+		return nil, ErrAuthenticationFailed
+	}
+	return message, nil
+}
+
+// SealAnonymous encrypts message to recipient with an ephemeral sender key, in the
+// format of libsodium's crypto_box_seal. The sender cannot be identified and cannot
+// decrypt the result. The ephemeral key is read from rand.
+func SealAnonymous(rand io.Reader, message []byte, recipient *[32]byte) ([]byte, error) {
+	return box.SealAnonymous(nil, message, recipient, drbg.Default(rand))
+}
+
+// OpenAnonymous opens an envelope produced by SealAnonymous or crypto_box_seal.
+func OpenAnonymous(envelope []byte, publicKey, privateKey *[32]byte) ([]byte, error) {
+	if len(envelope) < box.AnonymousOverhead {
+		return nil, ErrInvalidEnvelope
+	}
+
+	message, ok := box.OpenAnonymous(nil, envelope, publicKey, privateKey)
+	if !ok {
+		return nil, ErrAuthenticationFailed
+	}
+	return message, nil
+}
@@ -0,0 +1,145 @@
+package chacha20salsa20
+
+import (
+	"bytes"
+	"testing"
+
+	"golang.org/x/crypto/curve25519"
+)
+
+// naclKeyPair returns the Curve25519 key pair whose private key is 32 bytes of b.
+func naclKeyPair(t *testing.T, b byte) (publicKey, privateKey *[32]byte) {
+	t.Helper()
+	privateKey = (*[32]byte)(bytes.Repeat([]byte{b}, 32))
+	pub, err := curve25519.X25519(privateKey[:], curve25519.Basepoint)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return (*[32]byte)(pub), privateKey
+}
+
+// TestSecretboxVector opens a box made by the C implementation of NaCl, taken from
+// the golang.org/x/crypto/nacl/secretbox tests.
+func TestSecretboxVector(t *testing.T) {
+	key := bytes.Repeat([]byte{1}, 32)
+	nonce := bytes.Repeat([]byte{2}, 24)
+	message := bytes.Repeat([]byte{3}, 64)
+	envelope := append(bytes.Clone(nonce), decodeHex(t, "8442bc313f4626f1359e3b50122b6ce6fe66ddfe7d39d14e637eb4fd5b45bead"+
+		"ab55198df6ab5368439792a23c87db70acb6156dc5ef957ac04f6276cf6093b8"+
+		"4be77ff0849cc33e34b7254d5a8f65ad")...)
+
+	got, err := OpenSecretbox(key, envelope)
+	if err != nil || !bytes.Equal(got, message) {
+		t.Errorf("OpenSecretbox = %x, %v", got, err)
+	}
+	sealed, err := SealSecretbox(bytes.NewReader(nonce), key, message)
+	if err != nil || !bytes.Equal(sealed, envelope) {
+		t.Errorf("SealSecretbox = %x, %v, want %x", sealed, err, envelope)
+	}
+}
+
+// TestBoxVector opens a box made by the C implementation of NaCl from the holder of
+// private key 2 to the holder of private key 1.
+func TestBoxVector(t *testing.T) {
+	pub1, priv1 := naclKeyPair(t, 1)
+	pub2, priv2 := naclKeyPair(t, 2)
+	nonce := bytes.Repeat([]byte{4}, 24)
+	message := bytes.Repeat([]byte{3}, 64)
+	envelope := append(bytes.Clone(nonce), decodeHex(t, "78ea30b19d2341ebbdba54180f821eec265cf86312549bea8a37652a8bb94f07"+
+		"b78a73ed1708085e6ddd0e943bbdeb8755079a37eb31d86163ce241164a47629"+
+		"c0539f330b4914cd135b3855bc2a2dfc")...)
+
+	got, err := OpenBox(envelope, pub2, priv1)
+	if err != nil || !bytes.Equal(got, message) {
+		t.Errorf("OpenBox = %x, %v", got, err)
+	}
+	sealed, err := SealBox(bytes.NewReader(nonce), message, pub1, priv2)
+	if err != nil || !bytes.Equal(sealed, envelope) {
+		t.Errorf("SealBox = %x, %v, want %x", sealed, err, envelope)
+	}
+}
+
+// TestSealedBoxVectors use boxes made by libsodium's crypto_box_seal, taken from the
+// golang.org/x/crypto/nacl/box tests.
+func TestSealedBoxVectors(t *testing.T) {
+	pub, priv := naclKeyPair(t, 1)
+	message := bytes.Repeat([]byte{3}, 64)
+
+	fixture := decodeHex(t, "3462e0640728247a6f581e3812850d6edc3dcad1ea5d8184c072f62fb65cb357"+
+		"e27ffa8b76f41656bc66a0882c4d359568410665746d27462a700f01e314f382"+
+		"edd7aae9064879b0f8ba7b88866f88f5e4fbd7649c850541877f9f33ebd25d46"+
+		"d9cbcce09b69a9ba07f0eb1d105d4264")
+	got, err := OpenAnonymous(fixture, pub, priv)
+	if err != nil || !bytes.Equal(got, message) {
+		t.Errorf("OpenAnonymous = %x, %v", got, err)
+	}
+
+	// libsodium with a random source that always returns 5
+	want := decodeHex(t, "50a61409b1ddd0325e9b16b700e719e9772c07000b1bd7786e907c653d20495d"+
+		"2af1697137a53b1b1dfc9befc49b6eeb38f86be720e155eb2be61976d2efb34d"+
+		"67ecd44a6ad634625eb9c288bfc883431a84ab0f5557dfe673aa6f74c19f033e"+
+		"648a947358cfcc606397fa1747d5219a")
+	sealed, err := SealAnonymous(bytes.NewReader(bytes.Repeat([]byte{5}, 32)), message, pub)
+	if err != nil || !bytes.Equal(sealed, want) {
+		t.Errorf("SealAnonymous = %x, %v, want %x", sealed, err, want)
+	}
+}
+
+func TestNaClEnvelopeErrors(t *testing.T) {
+	key := make([]byte, 32)
+	pub1, priv1 := naclKeyPair(t, 1)
+	pub2, priv2 := naclKeyPair(t, 2)
+	message := []byte("message")
+
+	secret, err := SealSecretbox(nil, key, message)
+	if err != nil {
+		t.Fatal(err)
+	}
+	boxed, err := SealBox(nil, message, pub1, priv2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	anonymous, err := SealAnonymous(nil, message, pub1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	opens := []struct {
+		name     string
+		envelope []byte
+		minimum  int
+		open     func(envelope []byte) ([]byte, error)
+	}{
+		{"secretbox", secret, 24 + 16, func(e []byte) ([]byte, error) { return OpenSecretbox(key, e) }},
+		{"box", boxed, 24 + 16, func(e []byte) ([]byte, error) { return OpenBox(e, pub2, priv1) }},
+		{"sealed box", anonymous, 32 + 16, func(e []byte) ([]byte, error) { return OpenAnonymous(e, pub1, priv1) }},
+	}
+	for _, o := range opens {
+		if got, err := o.open(o.envelope); err != nil || !bytes.Equal(got, message) {
+			t.Fatalf("%s: round trip = %q, %v", o.name, got, err)
+		}
+		for _, n := range []int{0, o.minimum - 1} {
+			if _, err := o.open(o.envelope[:n]); err != ErrInvalidEnvelope {
+				t.Errorf("%s, %d bytes: got %v, want ErrInvalidEnvelope", o.name, n, err)
+			}
+		}
+		for _, i := range []int{0, o.minimum - 1, len(o.envelope) - 1} {
+			tampered := bytes.Clone(o.envelope)
+			tampered[i] ^= 1
+			if _, err := o.open(tampered); err != ErrAuthenticationFailed {
+				t.Errorf("%s, flipped byte %d: got %v, want ErrAuthenticationFailed", o.name, i, err)
+			}
+		}
+		if _, err := o.open(o.envelope[:o.minimum]); err != ErrAuthenticationFailed {
+			t.Errorf("%s, message cut off: got %v, want ErrAuthenticationFailed", o.name, err)
+		}
+	}
+
+	// the wrong recipient key cannot open a box
+	if _, err := OpenBox(boxed, pub2, priv2); err != ErrAuthenticationFailed {
+		t.Errorf("box, wrong key: got %v, want ErrAuthenticationFailed", err)
+	}
+	if _, err := OpenSecretbox(key[:16], secret); err != ErrInvalidKeySize {
+		t.Errorf("secretbox, 16-byte key: got %v, want ErrInvalidKeySize", err)
+	}
+}
@@ -1,21 +1,20 @@
 package chacha20salsa20
 
 import (
-	"crypto/rand"
 	"fmt"
 
+	"crypt/drbg"
+
 	"golang.org/x/crypto/salsa20"
 )
 
 func Salsa20() {
-	key := make([]byte, 32)
-	_, err := rand.Read(key)
+	key, err := drbg.Read(nil, 32)
 	if err != nil {
 		panic(err)
 	}
 
-	nounce := make([]byte, 8)
-	_, err = rand.Read(nounce)
+	nounce, err := drbg.Read(nil, 8)
 	if err != nil {
 		panic(err)
 	}
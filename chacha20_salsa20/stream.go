@@ -0,0 +1,38 @@
+package chacha20salsa20
+
+import (
+	"io"
+
+	"crypt/aeadstream"
+
+	"golang.org/x/crypto/chacha20poly1305"
+)
+
+// SegmentSize is the amount of plaintext sealed into each segment of an encrypted stream.
+const SegmentSize = aeadstream.SegmentSize
+
+// NewStreamEncrypter returns a writer that encrypts its input incrementally in
+// SegmentSize segments with ChaCha20-Poly1305, using the STREAM framing of the
+// aeadstream package. Close must be called to write the final segment; it also
+// closes w if w is an io.Closer. The nonce prefix is read from rand.
+func NewStreamEncrypter(rand io.Reader, key []byte, w io.Writer) (io.WriteCloser, error) {
+	aead, err := chacha20poly1305.New(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return aeadstream.NewEncrypter(rand, aead, w)
+}
+
+// NewStreamDecrypter returns a reader that authenticates and decrypts a stream
+// produced by NewStreamEncrypter. Plaintext is only released after its segment has
+// been authenticated; truncated, reordered or extended streams fail with
+// ErrAuthenticationFailed, as OpenChaCha20Poly1305 does.
+func NewStreamDecrypter(key []byte, r io.Reader) (io.Reader, error) {
+	aead, err := chacha20poly1305.New(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return aeadstream.NewDecrypterWithError(aead, r, ErrAuthenticationFailed)
+}
@@ -0,0 +1,65 @@
+package chacha20salsa20
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+
+	"crypt/aeadstream"
+
+	"golang.org/x/crypto/chacha20poly1305"
+)
+
+// TestStreamIsChaCha20Poly1305 checks that the stream is plain aeadstream framing
+// over ChaCha20-Poly1305 with the 12-byte nonce of RFC 8439.
+func TestStreamIsChaCha20Poly1305(t *testing.T) {
+	key := bytes.Repeat([]byte{9}, 32)
+	plaintext := bytes.Repeat([]byte("segment"), SegmentSize/3)
+
+	var buf bytes.Buffer
+	w, err := NewStreamEncrypter(nil, key, &buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.Write(plaintext)
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	aead, _ := chacha20poly1305.New(key)
+	r, err := aeadstream.NewDecrypter(aead, &buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := io.ReadAll(r); err != nil || !bytes.Equal(got, plaintext) {
+		t.Errorf("chacha20poly1305 could not read the stream: %v", err)
+	}
+}
+
+func TestStreamErrors(t *testing.T) {
+	key := make([]byte, 32)
+	var buf bytes.Buffer
+	w, err := NewStreamEncrypter(nil, key, &buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.Write([]byte("authenticated"))
+	w.Close()
+
+	r, err := NewStreamDecrypter(key, bytes.NewReader(append(buf.Bytes(), 0)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = io.ReadAll(r)
+	if !errors.Is(err, ErrAuthenticationFailed) || !errors.Is(err, aeadstream.ErrAuthenticationFailed) {
+		t.Errorf("extended stream: got %v, want ErrAuthenticationFailed", err)
+	}
+
+	if _, err := NewStreamDecrypter(key, bytes.NewReader(nil)); err != ErrInvalidStream {
+		t.Errorf("empty stream: got %v, want ErrInvalidStream", err)
+	}
+	if _, err := NewStreamEncrypter(nil, key[:16], io.Discard); err == nil {
+		t.Error("16-byte key accepted")
+	}
+}
@@ -3,16 +3,19 @@ package dh
 import (
 	"crypto/rand"
 	"fmt"
+	"io"
 	"math/big"
+
+	"crypt/drbg"
 )
 
-// Generate a private key (random number)
-func generatePrivateKey(prime *big.Int) *big.Int {
-	privateKey, err := rand.Int(rand.Reader, prime)
+// Generate a private key (random number) from random
+func generatePrivateKey(random io.Reader, prime *big.Int) (*big.Int, error) {
+	privateKey, err := rand.Int(drbg.Default(random), prime)
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
-	return privateKey
+	return privateKey, nil
 }
 
 // Compute public key: g^privateKey mod p
@@ -32,11 +35,17 @@ func DH_Plain() {
 	base := big.NewInt(5)                        // Base (g)
 
 	// Alice generates private & public key
-	alicePrivate := generatePrivateKey(prime)
+	alicePrivate, err := generatePrivateKey(nil, prime)
+	if err != nil {
+		panic(err)
+	}
 	alicePublic := computePublicKey(base, alicePrivate, prime)
 
 	// Bob generates private & public key
-	bobPrivate := generatePrivateKey(prime)
+	bobPrivate, err := generatePrivateKey(nil, prime)
+	if err != nil {
+		panic(err)
+	}
 	bobPublic := computePublicKey(base, bobPrivate, prime)
 
 	// Exchange public keys & compute shared secret
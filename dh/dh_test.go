@@ -0,0 +1,38 @@
+package dh
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"crypt/drbg"
+)
+
+func TestGeneratePrivateKeyDeterministic(t *testing.T) {
+	prime := big.NewInt(0).SetBytes(bytes.Repeat([]byte{0xff}, 32))
+	a, err := generatePrivateKey(drbg.NewDeterministic([]byte("dh")), prime)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := generatePrivateKey(drbg.NewDeterministic([]byte("dh")), prime)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.Cmp(b) != 0 || a.Cmp(prime) >= 0 {
+		t.Errorf("generatePrivateKey gave %v and %v", a, b)
+	}
+}
+
+func TestGenerateECDHKeyDeterministic(t *testing.T) {
+	a, err := generateECDHKey(drbg.NewDeterministic([]byte("ecdh")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := generateECDHKey(drbg.NewDeterministic([]byte("ecdh")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !a.Equal(b) {
+		t.Error("the same seed gave different ECDH keys")
+	}
+}
@@ -3,15 +3,34 @@ package dh
 import (
 	"bytes"
 	"crypto/ecdh"
-	"crypto/rand"
 	"encoding/base64"
 	"encoding/hex"
 	"fmt"
+	"io"
+
+	"crypt/drbg"
 )
 
+// generateECDHKey generates a P-256 private key from random. Unlike
+// ecdh.Curve.GenerateKey it reads the scalar directly, so a deterministic reader
+// always produces the same key.
+func generateECDHKey(random io.Reader) (*ecdh.PrivateKey, error) {
+	for {
+		scalar, err := drbg.Read(random, 32)
+		if err != nil {
+			return nil, err
+		}
+
+		// retry the rare scalars that are zero or not below the group order
+		if key, err := ecdh.P256().NewPrivateKey(scalar); err == nil {
+			return key, nil
+		}
+	}
+}
+
 func ECC_DH() {
 	// Alice
-	alicePrivateKey, err := ecdh.P256().GenerateKey(rand.Reader)
+	alicePrivateKey, err := generateECDHKey(nil)
 	if err != nil {
 		panic(err)
 	}
@@ -22,7 +41,7 @@ func ECC_DH() {
 	fmt.Println("public key ALICE(len): ", len(alicePublicKey.Bytes()))
 
 	// Bob
-	bobPrivateKey, err := ecdh.P256().GenerateKey(rand.Reader)
+	bobPrivateKey, err := generateECDHKey(nil)
 	if err != nil {
 		panic(err)
 	}
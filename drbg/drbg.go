@@ -0,0 +1,63 @@
+// Package drbg supplies the randomness used by the key, nonce and private-key
+// generators across this module: crypto/rand by default, or a seeded deterministic
+// generator so that tests can reproduce keys and ciphertexts exactly.
+//
+// Every function in the module that takes a rand io.Reader passes it through
+// Default, so a nil reader always means crypto/rand.
+package drbg
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"io"
+
+	"golang.org/x/crypto/chacha20"
+)
+
+// Default returns r, or crypto/rand.Reader when r is nil.
+func Default(r io.Reader) io.Reader {
+	if r == nil {
+		return rand.Reader
+	}
+
+	return r
+}
+
+// Read returns n bytes read from r, or from crypto/rand when r is nil.
+func Read(r io.Reader, n int) ([]byte, error) {
+	b := make([]byte, n)
+	if _, err := io.ReadFull(Default(r), b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// Deterministic is a deterministic random bit generator: the ChaCha20 keystream
+// under SHA-256(seed) with an all-zero nonce. The same seed always yields the same
+// bytes, so it must only be used for tests and reproducible vectors, never for real keys.
+type Deterministic struct {
+	stream *chacha20.Cipher
+}
+
+// NewDeterministic returns a Deterministic reader seeded with seed.
+func NewDeterministic(seed []byte) *Deterministic {
+	key := sha256.Sum256(seed)
+	stream, err := chacha20.NewUnauthenticatedCipher(key[:], make([]byte, chacha20.NonceSize))
+	if err != nil {
+		panic(err) // key and nonce sizes are fixed
+	}
+
+	return &Deterministic{stream: stream}
+}
+
+// Read fills p with the next bytes of the keystream. It never fails, but panics
+// once the 256 GiB keystream of a single seed is exhausted.
+func (d *Deterministic) Read(p []byte) (int, error) {
+	for i := range p {
+		p[i] = 0
+	}
+	d.stream.XORKeyStream(p, p)
+
+	return len(p), nil
+}
@@ -0,0 +1,76 @@
+package drbg_test
+
+import (
+	"bytes"
+	"testing"
+
+	"crypt/aes"
+	chacha "crypt/chacha20_salsa20"
+	"crypt/drbg"
+)
+
+func TestRead(t *testing.T) {
+	a, err := drbg.Read(nil, 32)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := drbg.Read(nil, 32)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(a) != 32 || bytes.Equal(a, b) {
+		t.Errorf("Read(nil, 32) returned %x then %x", a, b)
+	}
+
+	if _, err := drbg.Read(bytes.NewReader(make([]byte, 8)), 16); err == nil {
+		t.Error("Read from a short reader did not fail")
+	}
+}
+
+func TestDeterministic(t *testing.T) {
+	a, _ := drbg.Read(drbg.NewDeterministic([]byte("seed")), 64)
+	b, _ := drbg.Read(drbg.NewDeterministic([]byte("seed")), 64)
+	c, _ := drbg.Read(drbg.NewDeterministic([]byte("other seed")), 64)
+	if !bytes.Equal(a, b) {
+		t.Error("the same seed gave different bytes")
+	}
+	if bytes.Equal(a, c) {
+		t.Error("different seeds gave the same bytes")
+	}
+}
+
+// TestReproducibleKeysAndCiphertexts checks that a fixed seed reproduces keys and
+// ciphertexts exactly through the public APIs that take a rand io.Reader.
+func TestReproducibleKeysAndCiphertexts(t *testing.T) {
+	seal := func() (key, envelope, box []byte) {
+		rand := drbg.NewDeterministic([]byte("reproducible"))
+		key, err := aes.NewKeyFromReader(rand, aes.AES256)
+		if err != nil {
+			t.Fatal(err)
+		}
+		envelope, err = aes.SealGCM(rand, key, []byte("attack at dawn"), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		public, private, err := chacha.GenerateBoxKeyPair(rand)
+		if err != nil {
+			t.Fatal(err)
+		}
+		box, err = chacha.SealBox(rand, []byte("attack at dawn"), public, private)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return key, envelope, box
+	}
+
+	key1, envelope1, box1 := seal()
+	key2, envelope2, box2 := seal()
+	if !bytes.Equal(key1, key2) || !bytes.Equal(envelope1, envelope2) || !bytes.Equal(box1, box2) {
+		t.Error("the same seed did not reproduce the keys and ciphertexts")
+	}
+
+	plaintext, err := aes.OpenGCM(key1, envelope1, nil)
+	if err != nil || string(plaintext) != "attack at dawn" {
+		t.Errorf("OpenGCM = %q, %v", plaintext, err)
+	}
+}
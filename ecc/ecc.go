@@ -2,14 +2,27 @@ package ecc
 
 import (
 	"crypto/ed25519"
-	"crypto/rand"
 	"encoding/base64"
 	"encoding/hex"
 	"fmt"
+	"io"
+
+	"crypt/drbg"
 )
 
+// generateKey derives an Ed25519 key pair from a seed read from random.
+func generateKey(random io.Reader) (ed25519.PublicKey, ed25519.PrivateKey, error) {
+	seed, err := drbg.Read(random, ed25519.SeedSize)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	privateKey := ed25519.NewKeyFromSeed(seed)
+	return privateKey.Public().(ed25519.PublicKey), privateKey, nil
+}
+
 func ECC_DS() {
-	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	publicKey, privateKey, err := generateKey(nil)
 	if err != nil {
 		panic(err)
 	}
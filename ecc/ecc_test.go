@@ -0,0 +1,27 @@
+package ecc
+
+import (
+	"crypto/ed25519"
+	"testing"
+
+	"crypt/drbg"
+)
+
+func TestGenerateKeyDeterministic(t *testing.T) {
+	publicA, privateA, err := generateKey(drbg.NewDeterministic([]byte("ed25519")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	publicB, privateB, err := generateKey(drbg.NewDeterministic([]byte("ed25519")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !publicA.Equal(publicB) || !privateA.Equal(privateB) {
+		t.Error("the same seed gave different key pairs")
+	}
+
+	signature := ed25519.Sign(privateA, []byte("message"))
+	if !ed25519.Verify(publicA, []byte("message"), signature) {
+		t.Error("signature from the generated key does not verify")
+	}
+}
@@ -0,0 +1,52 @@
+package legacy
+
+import (
+	"crypto/cipher"
+
+	"crypt/blockcipher"
+
+	"golang.org/x/crypto/blowfish"
+)
+
+func init() {
+	blockcipher.Register(blockcipher.Cipher{
+		Name:      "Blowfish",
+		BlockSize: blowfish.BlockSize,
+		KeySizes:  blowfishKeySizes,
+		New:       insecure(newBlowfish),
+	})
+}
+
+// EncryptBlowfish encrypts plaintext of any length with Blowfish in the given mode.
+// It fails with ErrInsecureDisabled unless AllowInsecure is set.
+func EncryptBlowfish(mode Mode, key, iv, plaintext []byte) ([]byte, error) {
+	block, err := blockcipher.New("Blowfish", key)
+	if err != nil {
+		return nil, err
+	}
+
+	return blockcipher.Encrypt(block, mode, iv, plaintext)
+}
+
+// DecryptBlowfish decrypts a ciphertext produced by EncryptBlowfish with the same mode.
+func DecryptBlowfish(mode Mode, key, iv, ciphertext []byte) ([]byte, error) {
+	block, err := blockcipher.New("Blowfish", key)
+	if err != nil {
+		return nil, err
+	}
+
+	return blockcipher.Decrypt(block, mode, iv, ciphertext)
+}
+
+// blowfishKeySizes lists every key length Blowfish accepts, 4 to 56 bytes.
+var blowfishKeySizes = func() []int {
+	sizes := make([]int, 0, 56-4+1)
+	for n := 4; n <= 56; n++ {
+		sizes = append(sizes, n)
+	}
+	return sizes
+}()
+
+func newBlowfish(key []byte) (cipher.Block, error) {
+	return blowfish.NewCipher(key)
+}
@@ -0,0 +1,43 @@
+package legacy
+
+import (
+	"crypto/cipher"
+
+	"crypt/blockcipher"
+
+	"golang.org/x/crypto/cast5"
+)
+
+func init() {
+	blockcipher.Register(blockcipher.Cipher{
+		Name:      "CAST5",
+		BlockSize: cast5.BlockSize,
+		KeySizes:  []int{cast5.KeySize},
+		New:       insecure(newCAST5),
+	})
+}
+
+// EncryptCAST5 encrypts plaintext of any length with CAST5 in the given mode.
+// It fails with ErrInsecureDisabled unless AllowInsecure is set.
+func EncryptCAST5(mode Mode, key, iv, plaintext []byte) ([]byte, error) {
+	block, err := blockcipher.New("CAST5", key)
+	if err != nil {
+		return nil, err
+	}
+
+	return blockcipher.Encrypt(block, mode, iv, plaintext)
+}
+
+// DecryptCAST5 decrypts a ciphertext produced by EncryptCAST5 with the same mode.
+func DecryptCAST5(mode Mode, key, iv, ciphertext []byte) ([]byte, error) {
+	block, err := blockcipher.New("CAST5", key)
+	if err != nil {
+		return nil, err
+	}
+
+	return blockcipher.Decrypt(block, mode, iv, ciphertext)
+}
+
+func newCAST5(key []byte) (cipher.Block, error) {
+	return cast5.NewCipher(key)
+}
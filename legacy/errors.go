@@ -0,0 +1,7 @@
+package legacy
+
+import "errors"
+
+// ErrInsecureDisabled is returned when a 64-bit block cipher is used without
+// setting AllowInsecure.
+var ErrInsecureDisabled = errors.New("legacy: cipher is insecure and disabled, set legacy.AllowInsecure to use it")
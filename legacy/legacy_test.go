@@ -0,0 +1,106 @@
+package legacy
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	"crypt/blockcipher"
+)
+
+func allowInsecure(t *testing.T) {
+	t.Helper()
+	old := AllowInsecure
+	AllowInsecure = true
+	t.Cleanup(func() { AllowInsecure = old })
+}
+
+type legacyCipher struct {
+	name    string
+	encrypt func(Mode, []byte, []byte, []byte) ([]byte, error)
+	decrypt func(Mode, []byte, []byte, []byte) ([]byte, error)
+}
+
+var vectors = []struct {
+	cipher          legacyCipher
+	key, plain, out string
+}{
+	// Eric Young's Blowfish test vectors, first entry
+	{legacyCipher{"Blowfish", EncryptBlowfish, DecryptBlowfish},
+		"0000000000000000", "0000000000000000", "4ef997456198dd78"},
+	// RFC 2144, appendix B.1, 128-bit key
+	{legacyCipher{"CAST5", EncryptCAST5, DecryptCAST5},
+		"0123456712345678234567893456789a", "0123456789abcdef", "238b4fe5847e44b2"},
+	// NIST SP 800-67, appendix B, three-key ECB example
+	{legacyCipher{"3DES", EncryptTripleDES, DecryptTripleDES},
+		"0123456789abcdef23456789abcdef01456789abcdef0123",
+		hex.EncodeToString([]byte("The qufck brown fox jump")),
+		"a826fd8ce53b855fcce21c8112256fe668d5c05dd9b6b900"},
+	// Twofish paper, I=1 of the 128-bit ECB table
+	{legacyCipher{"Twofish", EncryptTwofish, DecryptTwofish},
+		"00000000000000000000000000000000", "00000000000000000000000000000000",
+		"9f589f5cf6122c32b6bfec2f2ae8c35a"},
+}
+
+func TestVectors(t *testing.T) {
+	allowInsecure(t)
+	for _, v := range vectors {
+		key, _ := hex.DecodeString(v.key)
+		plain, _ := hex.DecodeString(v.plain)
+		want, _ := hex.DecodeString(v.out)
+
+		block, err := blockcipher.New(v.cipher.name, key)
+		if err != nil {
+			t.Fatalf("%s: %v", v.cipher.name, err)
+		}
+		bs := block.BlockSize()
+		got := make([]byte, len(plain))
+		for i := 0; i < len(plain); i += bs {
+			block.Encrypt(got[i:i+bs], plain[i:i+bs])
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("%s: got %x, want %x", v.cipher.name, got, want)
+		}
+
+		// ECB adds a block of PKCS#7 padding after the vector
+		ciphertext, err := v.cipher.encrypt(ECB, key, nil, plain)
+		if err != nil || !bytes.Equal(ciphertext[:len(want)], want) {
+			t.Errorf("%s ECB: got %x, %v, want prefix %x", v.cipher.name, ciphertext, err, want)
+			continue
+		}
+		if got, err := v.cipher.decrypt(ECB, key, nil, ciphertext); err != nil || !bytes.Equal(got, plain) {
+			t.Errorf("%s ECB: decrypted %x, %v, want %x", v.cipher.name, got, err, plain)
+		}
+	}
+}
+
+func TestInsecureDisabled(t *testing.T) {
+	old := AllowInsecure
+	AllowInsecure = false
+	defer func() { AllowInsecure = old }()
+
+	for _, v := range vectors {
+		key, _ := hex.DecodeString(v.key)
+		c, err := blockcipher.Lookup(v.cipher.name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		iv := make([]byte, c.BlockSize)
+		_, err = v.cipher.encrypt(CBC, key, iv, []byte("plaintext"))
+		if v.cipher.name == "Twofish" {
+			if err != nil {
+				t.Errorf("Twofish is not gated, got %v", err)
+			}
+			continue
+		}
+		if err != ErrInsecureDisabled {
+			t.Errorf("%s: got %v, want ErrInsecureDisabled", v.cipher.name, err)
+		}
+		if _, err := v.cipher.decrypt(CBC, key, iv, make([]byte, 2*c.BlockSize)); err != ErrInsecureDisabled {
+			t.Errorf("%s decrypt: got %v, want ErrInsecureDisabled", v.cipher.name, err)
+		}
+		if _, err := blockcipher.New(v.cipher.name, key); err != ErrInsecureDisabled {
+			t.Errorf("blockcipher.New(%q): got %v, want ErrInsecureDisabled", v.cipher.name, err)
+		}
+	}
+}
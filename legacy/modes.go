@@ -0,0 +1,41 @@
+// Package legacy provides block ciphers that are still met in archives and older
+// protocols: Blowfish, Twofish, CAST5 and Triple-DES. They are registered in the
+// blockcipher registry and use its shared modes of operation.
+package legacy
+
+import (
+	"crypto/cipher"
+
+	"crypt/blockcipher"
+)
+
+// AllowInsecure must be set to true before Blowfish, CAST5 or Triple-DES can be used.
+// Their 64-bit blocks make birthday attacks such as Sweet32 practical after a few
+// dozen gigabytes under one key, so they are only kept for decrypting old data.
+// Twofish has a 128-bit block and is not gated.
+var AllowInsecure = false
+
+// Mode is a mode of operation for the legacy block ciphers. The modes are the
+// shared ones of the blockcipher package, and so are the errors they return.
+type Mode = blockcipher.Mode
+
+const (
+	CBC = blockcipher.CBC
+	CTR = blockcipher.CTR
+	GCM = blockcipher.GCM // Twofish only, the others have a 64-bit block
+	CFB = blockcipher.CFB
+	OFB = blockcipher.OFB
+	ECB = blockcipher.ECB
+)
+
+// insecure wraps the constructor of a gated cipher so that it fails unless
+// AllowInsecure is set, including when it is reached through the registry.
+func insecure(newCipher func(key []byte) (cipher.Block, error)) func(key []byte) (cipher.Block, error) {
+	return func(key []byte) (cipher.Block, error) {
+		if !AllowInsecure {
+			return nil, ErrInsecureDisabled
+		}
+
+		return newCipher(key)
+	}
+}
@@ -0,0 +1,37 @@
+package legacy
+
+import (
+	"crypto/des"
+
+	"crypt/blockcipher"
+)
+
+func init() {
+	blockcipher.Register(blockcipher.Cipher{
+		Name:      "3DES",
+		BlockSize: des.BlockSize,
+		KeySizes:  []int{24},
+		New:       insecure(des.NewTripleDESCipher),
+	})
+}
+
+// EncryptTripleDES encrypts plaintext of any length with three-key Triple-DES in the given mode.
+// It fails with ErrInsecureDisabled unless AllowInsecure is set.
+func EncryptTripleDES(mode Mode, key, iv, plaintext []byte) ([]byte, error) {
+	block, err := blockcipher.New("3DES", key)
+	if err != nil {
+		return nil, err
+	}
+
+	return blockcipher.Encrypt(block, mode, iv, plaintext)
+}
+
+// DecryptTripleDES decrypts a ciphertext produced by EncryptTripleDES with the same mode.
+func DecryptTripleDES(mode Mode, key, iv, ciphertext []byte) ([]byte, error) {
+	block, err := blockcipher.New("3DES", key)
+	if err != nil {
+		return nil, err
+	}
+
+	return blockcipher.Decrypt(block, mode, iv, ciphertext)
+}
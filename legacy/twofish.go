@@ -0,0 +1,42 @@
+package legacy
+
+import (
+	"crypto/cipher"
+
+	"crypt/blockcipher"
+
+	"golang.org/x/crypto/twofish"
+)
+
+func init() {
+	blockcipher.Register(blockcipher.Cipher{
+		Name:      "Twofish",
+		BlockSize: twofish.BlockSize,
+		KeySizes:  []int{16, 24, 32},
+		New:       newTwofish,
+	})
+}
+
+// EncryptTwofish encrypts plaintext of any length with Twofish in the given mode.
+func EncryptTwofish(mode Mode, key, iv, plaintext []byte) ([]byte, error) {
+	block, err := blockcipher.New("Twofish", key)
+	if err != nil {
+		return nil, err
+	}
+
+	return blockcipher.Encrypt(block, mode, iv, plaintext)
+}
+
+// DecryptTwofish decrypts a ciphertext produced by EncryptTwofish with the same mode.
+func DecryptTwofish(mode Mode, key, iv, ciphertext []byte) ([]byte, error) {
+	block, err := blockcipher.New("Twofish", key)
+	if err != nil {
+		return nil, err
+	}
+
+	return blockcipher.Decrypt(block, mode, iv, ciphertext)
+}
+
+func newTwofish(key []byte) (cipher.Block, error) {
+	return twofish.NewCipher(key)
+}
@@ -0,0 +1,71 @@
+package rc
+
+import (
+	"crypt/blockcipher"
+
+	"github.com/dgryski/go-rc5"
+	"github.com/dgryski/go-rc6"
+)
+
+const (
+	rc5BlockSize = 8
+	rc6BlockSize = 16
+)
+
+func init() {
+	blockcipher.Register(blockcipher.Cipher{Name: "RC5", BlockSize: rc5BlockSize, KeySizes: []int{16}, New: rc5.New})
+	blockcipher.Register(blockcipher.Cipher{Name: "RC6", BlockSize: rc6BlockSize, KeySizes: []int{16}, New: rc6.New})
+}
+
+// Mode is a mode of operation for the RC5 and RC6 block ciphers. The modes are the
+// shared ones of the blockcipher package, and so are the errors they return.
+type Mode = blockcipher.Mode
+
+const (
+	CBC = blockcipher.CBC
+	CTR = blockcipher.CTR
+	GCM = blockcipher.GCM // RC6 only, RC5 has a 64-bit block
+	CFB = blockcipher.CFB
+	OFB = blockcipher.OFB
+	ECB = blockcipher.ECB
+)
+
+// EncryptRC5 encrypts plaintext of any length with RC5-32/12/16 in the given mode.
+func EncryptRC5(mode Mode, key, iv, plaintext []byte) ([]byte, error) {
+	block, err := rc5.New(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return blockcipher.Encrypt(block, mode, iv, plaintext)
+}
+
+// DecryptRC5 decrypts a ciphertext produced by EncryptRC5 with the same mode.
+func DecryptRC5(mode Mode, key, iv, ciphertext []byte) ([]byte, error) {
+	block, err := rc5.New(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return blockcipher.Decrypt(block, mode, iv, ciphertext)
+}
+
+// EncryptRC6 encrypts plaintext of any length with RC6-32/20/16 in the given mode.
+func EncryptRC6(mode Mode, key, iv, plaintext []byte) ([]byte, error) {
+	block, err := rc6.New(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return blockcipher.Encrypt(block, mode, iv, plaintext)
+}
+
+// DecryptRC6 decrypts a ciphertext produced by EncryptRC6 with the same mode.
+func DecryptRC6(mode Mode, key, iv, ciphertext []byte) ([]byte, error) {
+	block, err := rc6.New(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return blockcipher.Decrypt(block, mode, iv, ciphertext)
+}
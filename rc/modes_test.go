@@ -0,0 +1,93 @@
+package rc
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"crypt/blockcipher"
+)
+
+// demoMessage is the 39-byte message of the Rc5 and Rc6 demos; it is not a
+// multiple of either block size.
+var demoMessage = []byte("This is a secret message from mustafa!!")
+
+type rcCipher struct {
+	name      string
+	blockSize int
+	encrypt   func(Mode, []byte, []byte, []byte) ([]byte, error)
+	decrypt   func(Mode, []byte, []byte, []byte) ([]byte, error)
+}
+
+var rcCiphers = []rcCipher{
+	{"RC5", rc5BlockSize, EncryptRC5, DecryptRC5},
+	{"RC6", rc6BlockSize, EncryptRC6, DecryptRC6},
+}
+
+// ivSize is the IV length mode expects for a cipher with the given block size.
+func ivSize(mode Mode, blockSize int) int {
+	if mode == GCM {
+		return 12
+	}
+	return blockSize
+}
+
+func TestModesRoundTrip(t *testing.T) {
+	key := []byte("0123456789abcdef")
+	for _, c := range rcCiphers {
+		for _, mode := range []Mode{CBC, CTR, GCM, CFB, OFB, ECB} {
+			iv := bytes.Repeat([]byte{7}, ivSize(mode, c.blockSize))
+			ciphertext, err := c.encrypt(mode, key, iv, demoMessage)
+			if c.blockSize != 16 && mode == GCM {
+				if err != blockcipher.ErrUnsupportedMode {
+					t.Errorf("%s GCM: got %v, want ErrUnsupportedMode", c.name, err)
+				}
+				continue
+			}
+			if err != nil {
+				t.Fatalf("%s mode %d: %v", c.name, mode, err)
+			}
+			if bytes.Contains(ciphertext, demoMessage[:8]) {
+				t.Errorf("%s mode %d: ciphertext contains plaintext", c.name, mode)
+			}
+			got, err := c.decrypt(mode, key, iv, ciphertext)
+			if err != nil || !bytes.Equal(got, demoMessage) {
+				t.Errorf("%s mode %d: got %q, %v", c.name, mode, got, err)
+			}
+		}
+	}
+}
+
+func TestModesIVSize(t *testing.T) {
+	key := []byte("0123456789abcdef")
+	for _, c := range rcCiphers {
+		for _, mode := range []Mode{CBC, CTR, CFB, OFB} {
+			iv := make([]byte, c.blockSize-1)
+			var ivErr blockcipher.IVSizeError
+			if _, err := c.encrypt(mode, key, iv, demoMessage); !errors.As(err, &ivErr) {
+				t.Errorf("%s mode %d encrypt: got %v, want IVSizeError", c.name, mode, err)
+			}
+			if _, err := c.decrypt(mode, key, iv, make([]byte, 4*c.blockSize)); !errors.As(err, &ivErr) {
+				t.Errorf("%s mode %d decrypt: got %v, want IVSizeError", c.name, mode, err)
+			}
+		}
+	}
+
+	var ivErr blockcipher.IVSizeError
+	if _, err := EncryptRC6(GCM, key, make([]byte, 16), demoMessage); !errors.As(err, &ivErr) || ivErr != 16 {
+		t.Errorf("RC6 GCM with a 16-byte nonce: got %v, want IVSizeError(16)", err)
+	}
+}
+
+func TestRC6GCMAuthenticates(t *testing.T) {
+	key := []byte("0123456789abcdef")
+	nonce := make([]byte, 12)
+	ciphertext, err := EncryptRC6(GCM, key, nonce, demoMessage)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ciphertext[0] ^= 1
+	if _, err := DecryptRC6(GCM, key, nonce, ciphertext); err != blockcipher.ErrAuthenticationFailed {
+		t.Errorf("got %v, want ErrAuthenticationFailed", err)
+	}
+}
@@ -1,29 +1,43 @@
 package rc
 
 import (
-	"crypto/rand"
-	"crypto/rc4"
 	"fmt"
+
+	"crypt/drbg"
 )
 
 func Rc4() {
-	key := make([]byte, 32) // 32 byte rc4 key - variable length
-	_, err := rand.Read(key)
+	key, err := drbg.Read(nil, 32) // 32 byte rc4 key - variable length
 	if err != nil {
 		panic(err)
 	}
 
-	cipherText := encryptrc4([]byte("This is a secret message from mustafa!!"), key) // Encrypt
-	fmt.Println(string(encryptrc4(cipherText, key)))                                 // Decrypt
+	cipherText, err := EncryptRC4(key, []byte("This is a secret message from mustafa!!"))
+	if err != nil {
+		fmt.Println(err.Error())
+		return
+	}
+	plainText, err := DecryptRC4(key, cipherText)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(string(plainText))
 }
 
-func encryptrc4(message, key []byte) []byte {
-	cipherText := make([]byte, len(message))
-	rc, err := rc4.NewCipher(key)
+// EncryptRC4 encrypts plaintext with RC4-drop[DefaultRC4Drop]. It returns
+// ErrInsecureDisabled unless AllowInsecure is set.
+func EncryptRC4(key, plaintext []byte) ([]byte, error) {
+	c, err := NewRC4Drop(key, DefaultRC4Drop)
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
 
-	rc.XORKeyStream(cipherText, message)
-	return cipherText
+	cipherText := make([]byte, len(plaintext))
+	c.XORKeyStream(cipherText, plaintext)
+	return cipherText, nil
+}
+
+// DecryptRC4 decrypts a ciphertext produced by EncryptRC4 with the same key.
+func DecryptRC4(key, ciphertext []byte) ([]byte, error) {
+	return EncryptRC4(key, ciphertext) // RC4 uses same function for encryption/decryption
 }
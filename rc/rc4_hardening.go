@@ -0,0 +1,164 @@
+package rc
+
+import (
+	"crypto/rc4"
+	"errors"
+	"io"
+
+	"crypt/drbg"
+)
+
+// DefaultRC4Drop is the number of initial keystream bytes discarded by RC4-drop[n]
+// when no other value is given, following the RC4-drop[3072] recommendation.
+const DefaultRC4Drop = 3072
+
+// AllowInsecure must be set to true before any RC4 cipher can be created. RC4 has
+// practical keystream biases and is prohibited in TLS (RFC 7465); it is only kept
+// for legacy interoperability and teaching.
+var AllowInsecure = false
+
+var (
+	// ErrInsecureDisabled is returned when RC4 is used without setting AllowInsecure.
+	ErrInsecureDisabled = errors.New("rc: RC4 is insecure and disabled, set rc.AllowInsecure to use it")
+
+	// ErrRC4Drop is returned for a negative drop count.
+	ErrRC4Drop = errors.New("rc: RC4 drop count must not be negative")
+)
+
+// NewRC4Drop returns an RC4 cipher that has already discarded the first drop bytes
+// of its keystream, removing the most strongly biased output (RC4-drop[n]).
+func NewRC4Drop(key []byte, drop int) (*rc4.Cipher, error) {
+	if !AllowInsecure {
+		return nil, ErrInsecureDisabled
+	}
+	if drop < 0 {
+		return nil, ErrRC4Drop
+	}
+
+	c, err := rc4.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	discard := make([]byte, drop)
+	c.XORKeyStream(discard, discard)
+
+	return c, nil
+}
+
+// RC4KeySchedule returns the permutation S produced by the RC4 key-scheduling
+// algorithm for key, for inspecting how key bytes leak into the initial state.
+// It does not encrypt anything and so is not gated by AllowInsecure.
+func RC4KeySchedule(key []byte) ([256]byte, error) {
+	if len(key) < 1 || len(key) > 256 {
+		return [256]byte{}, rc4.KeySizeError(len(key))
+	}
+
+	var s [256]byte
+	for i := range s {
+		s[i] = byte(i)
+	}
+	var j byte
+	for i := 0; i < 256; i++ {
+		j += s[i] + key[i%len(key)]
+		s[i], s[j] = s[j], s[i]
+	}
+
+	return s, nil
+}
+
+// rc4Output returns the first n keystream bytes generated from the permutation s.
+func rc4Output(s [256]byte, n int) []byte {
+	out := make([]byte, n)
+	var i, j byte
+	for k := range out {
+		i++
+		j += s[i]
+		s[i], s[j] = s[j], s[i]
+		out[k] = s[s[i]+s[j]]
+	}
+
+	return out
+}
+
+// RC4BiasReport holds the results of AnalyzeRC4Bias. An unbiased cipher would show
+// rates close to 1/256 for both measurements.
+type RC4BiasReport struct {
+	Keys int
+
+	// SecondByteZero counts keys whose second keystream byte was zero. Mantin and
+	// Shamir showed this happens with probability about 2/256.
+	SecondByteZero     int
+	SecondByteZeroRate float64
+
+	// FMSResolved counts WEP-style keys IV(3) || secret whose weak IV (3, 255, x)
+	// left the key schedule in the resolved condition of Fluhrer, Mantin and Shamir;
+	// FMSRecovered counts how many of those revealed the first secret key byte from
+	// the first keystream byte alone (about 5% instead of 1/256).
+	FMSResolved     int
+	FMSRecovered    int
+	FMSRecoveryRate float64
+}
+
+// AnalyzeRC4Bias measures the second-byte bias and the Fluhrer-Mantin-Shamir weak
+// key bias of raw RC4 over the given number of random keys read from rand. It is
+// meant for teaching and audits and, like RC4KeySchedule, is not gated by AllowInsecure.
+func AnalyzeRC4Bias(rand io.Reader, keys int) (RC4BiasReport, error) {
+	report := RC4BiasReport{Keys: keys}
+	rand = drbg.Default(rand)
+
+	key := make([]byte, 16)
+	wepKey := make([]byte, 3+13) // WEP-104: 24-bit IV followed by a 104-bit secret
+	wepKey[0], wepKey[1] = 3, 255
+	for k := 0; k < keys; k++ {
+		if _, err := io.ReadFull(rand, key); err != nil {
+			return RC4BiasReport{}, err
+		}
+		s, _ := RC4KeySchedule(key)
+		if rc4Output(s, 2)[1] == 0 {
+			report.SecondByteZero++
+		}
+
+		if _, err := io.ReadFull(rand, wepKey[2:]); err != nil {
+			return RC4BiasReport{}, err
+		}
+		s, _ = RC4KeySchedule(wepKey)
+		if guess, resolved := fmsGuess(wepKey[:3], rc4Output(s, 1)[0]); resolved {
+			report.FMSResolved++
+			if guess == wepKey[3] {
+				report.FMSRecovered++
+			}
+		}
+	}
+
+	if keys > 0 {
+		report.SecondByteZeroRate = float64(report.SecondByteZero) / float64(keys)
+	}
+	if report.FMSResolved > 0 {
+		report.FMSRecoveryRate = float64(report.FMSRecovered) / float64(report.FMSResolved)
+	}
+	return report, nil
+}
+
+// fmsGuess runs the first three steps of the key schedule on the known IV and, if
+// the state is resolved, predicts the first secret key byte from the first output byte.
+func fmsGuess(iv []byte, first byte) (byte, bool) {
+	var s [256]byte
+	for i := range s {
+		s[i] = byte(i)
+	}
+	var j byte
+	for i := 0; i < len(iv); i++ {
+		j += s[i] + iv[i]
+		s[i], s[j] = s[j], s[i]
+	}
+
+	if s[1] >= 3 || s[1]+s[s[1]] != 3 {
+		return 0, false
+	}
+
+	var inverse [256]byte
+	for i, v := range s {
+		inverse[v] = byte(i)
+	}
+	return inverse[first] - j - s[3], true
+}
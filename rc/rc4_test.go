@@ -0,0 +1,139 @@
+package rc
+
+import (
+	"bytes"
+	"crypto/rc4"
+	"errors"
+	"io"
+	"testing"
+	"testing/iotest"
+
+	"crypt/drbg"
+)
+
+func allowInsecure(t *testing.T) {
+	t.Helper()
+	old := AllowInsecure
+	AllowInsecure = true
+	t.Cleanup(func() { AllowInsecure = old })
+}
+
+func TestRC4Disabled(t *testing.T) {
+	old := AllowInsecure
+	AllowInsecure = false
+	defer func() { AllowInsecure = old }()
+
+	key := []byte("a key")
+	if _, err := EncryptRC4(key, []byte("message")); err != ErrInsecureDisabled {
+		t.Errorf("EncryptRC4: got %v, want ErrInsecureDisabled", err)
+	}
+	if _, err := DecryptRC4(key, []byte("message")); err != ErrInsecureDisabled {
+		t.Errorf("DecryptRC4: got %v, want ErrInsecureDisabled", err)
+	}
+	if _, err := NewRC4Drop(key, 0); err != ErrInsecureDisabled {
+		t.Errorf("NewRC4Drop: got %v, want ErrInsecureDisabled", err)
+	}
+}
+
+// TestRC4Drop checks that RC4-drop[n] is the raw RC4 keystream without its first n bytes.
+func TestRC4Drop(t *testing.T) {
+	allowInsecure(t)
+	key := []byte("Key")
+
+	raw, err := rc4.NewCipher(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stream := make([]byte, DefaultRC4Drop+64)
+	raw.XORKeyStream(stream, stream)
+
+	for _, n := range []int{0, 1, 256, DefaultRC4Drop} {
+		c, err := NewRC4Drop(key, n)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := make([]byte, 64)
+		c.XORKeyStream(got, got)
+		if !bytes.Equal(got, stream[n:n+64]) {
+			t.Errorf("drop %d: got %x, want %x", n, got, stream[n:n+64])
+		}
+	}
+
+	if _, err := NewRC4Drop(key, -1); err != ErrRC4Drop {
+		t.Errorf("drop -1: got %v, want ErrRC4Drop", err)
+	}
+
+	plaintext := []byte("Plaintext")
+	ct, err := EncryptRC4(key, plaintext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := make([]byte, len(plaintext))
+	for i := range plaintext {
+		want[i] = plaintext[i] ^ stream[DefaultRC4Drop+i]
+	}
+	if !bytes.Equal(ct, want) {
+		t.Errorf("EncryptRC4: got %x, want %x", ct, want)
+	}
+	if pt, err := DecryptRC4(key, ct); err != nil || !bytes.Equal(pt, plaintext) {
+		t.Errorf("DecryptRC4: got %q, %v", pt, err)
+	}
+}
+
+// TestRC4KeySchedule checks the KSA against the first bytes of the "Key"/"Plaintext"
+// example, whose ciphertext is BBF316E8D940AF0AD3.
+func TestRC4KeySchedule(t *testing.T) {
+	s, err := RC4KeySchedule([]byte("Key"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	plaintext := []byte("Plaintext")
+	want := []byte{0xbb, 0xf3, 0x16, 0xe8, 0xd9, 0x40, 0xaf, 0x0a, 0xd3}
+	keystream := rc4Output(s, len(plaintext))
+	for i := range plaintext {
+		if plaintext[i]^keystream[i] != want[i] {
+			t.Fatalf("got keystream %x, want ciphertext %x", keystream, want)
+		}
+	}
+
+	if _, err := RC4KeySchedule(nil); err != rc4.KeySizeError(0) {
+		t.Errorf("empty key: got %v, want KeySizeError(0)", err)
+	}
+}
+
+func TestAnalyzeRC4Bias(t *testing.T) {
+	const keys = 100000
+	report, err := AnalyzeRC4Bias(drbg.NewDeterministic([]byte("rc4 bias")), keys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Keys != keys {
+		t.Errorf("Keys = %d, want %d", report.Keys, keys)
+	}
+
+	// Mantin-Shamir: the second output byte is zero with probability 2/256.
+	if r := report.SecondByteZeroRate; r < 1.7/256 || r > 2.3/256 {
+		t.Errorf("SecondByteZeroRate = %.5f, want about %.5f", r, 2.0/256)
+	}
+
+	// FMS: a resolved weak IV leaks the first key byte about 5% of the time.
+	if report.FMSResolved < keys/100 {
+		t.Fatalf("only %d of %d weak IVs were resolved", report.FMSResolved, keys)
+	}
+	if r := report.FMSRecoveryRate; r < 0.03 || r > 0.07 {
+		t.Errorf("FMSRecoveryRate = %.4f, want about 0.05 (random guessing gives 1/256)", r)
+	}
+}
+
+func TestAnalyzeRC4BiasReaderError(t *testing.T) {
+	errRead := errors.New("read failed")
+	readers := map[string]io.Reader{
+		"first key":  iotest.ErrReader(errRead),
+		"WEP secret": io.MultiReader(bytes.NewReader(make([]byte, 20)), iotest.ErrReader(errRead)),
+	}
+	for name, r := range readers {
+		if _, err := AnalyzeRC4Bias(r, 10); err != errRead {
+			t.Errorf("%s: got %v, want the reader's error", name, err)
+		}
+	}
+}
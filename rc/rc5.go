@@ -1,41 +1,32 @@
 package rc
 
 import (
-	"crypto/rand"
 	"fmt"
 
-	"github.com/dgryski/go-rc5"
+	"crypt/drbg"
 )
 
+// Rc5 demonstrates RC5-32/12/16 in CBC mode. CBC hides the message but does not
+// authenticate it, and RC5-32 has a 64-bit block, so GCM is not available; use RC6
+// with GCM, or NewRC5 with a 64-bit word, when tampering has to be detected.
 func Rc5() {
-	key := make([]byte, 16) // 16 byte rc4 key - variable length
-	_, err := rand.Read(key)
+	key, err := drbg.Read(nil, 16) // 16 byte rc5 key
 	if err != nil {
 		panic(err)
 	}
 
-	cipherText := encryptrc5([]byte("This is a secret message from mustafa!!"), key) // Encrypt
-	fmt.Println(string(decryptrc5(cipherText, key)))                                 // Decrypt
-}
-
-func encryptrc5(message, key []byte) []byte {
-	cipherText := make([]byte, len(message))
-	rc, err := rc5.New(key)
+	iv, err := drbg.Read(nil, rc5BlockSize)
 	if err != nil {
 		panic(err)
 	}
 
-	rc.Encrypt(cipherText, message)
-	return cipherText
-}
-
-func decryptrc5(message, key []byte) []byte {
-	cipherText := make([]byte, len(message))
-	rc, err := rc5.New(key)
+	cipherText, err := EncryptRC5(CBC, key, iv, []byte("This is a secret message from mustafa!!")) // Encrypt
 	if err != nil {
 		panic(err)
 	}
-
-	rc.Decrypt(cipherText, message)
-	return cipherText
+	plainText, err := DecryptRC5(CBC, key, iv, cipherText) // Decrypt
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(string(plainText))
 }
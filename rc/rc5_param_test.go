@@ -0,0 +1,104 @@
+package rc
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	"github.com/dgryski/go-rc5"
+)
+
+func TestRC5Vectors(t *testing.T) {
+	cases := []struct {
+		w, r            int
+		key, plain, out string
+	}{
+		// RC5-32/12/16 examples from Rivest's RC5 paper
+		{32, 12, "00000000000000000000000000000000", "0000000000000000", "21a5dbee154b8f6d"},
+		{32, 12, "915f4619be41b2516355a50110a9ce91", "21a5dbee154b8f6d", "f7c013ac5b2b8952"},
+		{32, 12, "783348e75aeb0f2fd7b169bb8dc16787", "f7c013ac5b2b8952", "2f42b3b70369fc92"},
+		{32, 12, "dc49db1375a5584f6485b413b5f12baf", "2f42b3b70369fc92", "65c178b284d197cc"},
+		{32, 12, "5269f149d41ba0152497574d7f153125", "65c178b284d197cc", "eb44e415da319824"},
+
+		// draft-krovetz-rc6-rc5-vectors
+		{16, 16, "0001020304050607", "00010203", "23a8d72e"},
+		{32, 20, "000102030405060708090a0b0c0d0e0f", "0001020304050607", "2a0edc0e9431ff73"},
+		{64, 24, "000102030405060708090a0b0c0d0e0f1011121314151617",
+			"000102030405060708090a0b0c0d0e0f", "a46772820edbce0235abea32ae7178da"},
+	}
+
+	for _, c := range cases {
+		key, _ := hex.DecodeString(c.key)
+		plain, _ := hex.DecodeString(c.plain)
+		want, _ := hex.DecodeString(c.out)
+
+		block, err := NewRC5(key, c.w, c.r)
+		if err != nil {
+			t.Fatalf("RC5-%d/%d/%d: %v", c.w, c.r, len(key), err)
+		}
+		got := make([]byte, len(plain))
+		block.Encrypt(got, plain)
+		if !bytes.Equal(got, want) {
+			t.Errorf("RC5-%d/%d/%d: got %x, want %x", c.w, c.r, len(key), got, want)
+		}
+		block.Decrypt(got, got)
+		if !bytes.Equal(got, plain) {
+			t.Errorf("RC5-%d/%d/%d: decrypted %x, want %x", c.w, c.r, len(key), got, plain)
+		}
+	}
+}
+
+// TestRC5MatchesDemo checks that RC5-32/12/16 agrees with the cipher used by EncryptRC5.
+func TestRC5MatchesDemo(t *testing.T) {
+	key := []byte("0123456789abcdef")
+	iv := []byte("ivivivi!")
+	plaintext := []byte("RC5 with selectable word size and rounds")
+
+	want, err := EncryptRC5(CBC, key, iv, plaintext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := EncryptRC5WithParams(CBC, 32, 12, key, iv, plaintext)
+	if err != nil || !bytes.Equal(got, want) {
+		t.Errorf("got %x, %v, want %x", got, err, want)
+	}
+
+	a, _ := rc5.New(key)
+	b, _ := NewRC5(key, 32, 12)
+	x, y := make([]byte, 8), make([]byte, 8)
+	a.Encrypt(x, plaintext)
+	b.Encrypt(y, plaintext)
+	if !bytes.Equal(x, y) {
+		t.Errorf("block: got %x, want %x", y, x)
+	}
+}
+
+func TestRC5WithParamsRoundTrip(t *testing.T) {
+	plaintext := []byte("a plaintext that is not a multiple of any block size")
+	for _, w := range []int{16, 32, 64} {
+		key := bytes.Repeat([]byte{byte(w)}, 16)
+		iv := make([]byte, w/4)
+		for _, mode := range []Mode{CBC, CTR, CFB, OFB, ECB} {
+			ct, err := EncryptRC5WithParams(mode, w, 16, key, iv, plaintext)
+			if err != nil {
+				t.Fatalf("w=%d mode %d: %v", w, mode, err)
+			}
+			pt, err := DecryptRC5WithParams(mode, w, 16, key, iv, ct)
+			if err != nil || !bytes.Equal(pt, plaintext) {
+				t.Errorf("w=%d mode %d: got %q, %v", w, mode, pt, err)
+			}
+		}
+	}
+}
+
+func TestRC5ParamErrors(t *testing.T) {
+	if _, err := NewRC5(nil, 8, 12); err != ErrRC5WordSize {
+		t.Errorf("w=8: got %v, want ErrRC5WordSize", err)
+	}
+	if _, err := NewRC5(nil, 32, 256); err != ErrRC5Rounds {
+		t.Errorf("r=256: got %v, want ErrRC5Rounds", err)
+	}
+	if _, err := NewRC5(make([]byte, 256), 32, 12); err != ErrRC5KeySize {
+		t.Errorf("256-byte key: got %v, want ErrRC5KeySize", err)
+	}
+}
@@ -9,7 +9,7 @@ import (
 )
 
 func RAS_PSS() {
-	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	privateKey, err := generateKey(nil, 2048)
 	if err != nil {
 		fmt.Println("keyGen error: ", err.Error())
 		return
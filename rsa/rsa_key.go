@@ -0,0 +1,14 @@
+package rsa
+
+import (
+	"crypto/rsa"
+	"io"
+
+	"crypt/drbg"
+)
+
+// generateKey generates an RSA key of the given size from random. The standard library may mix in extra randomness,
+// so RSA keys are not guaranteed to be reproducible even from a deterministic reader.
+func generateKey(random io.Reader, bits int) (*rsa.PrivateKey, error) {
+	return rsa.GenerateKey(drbg.Default(random), bits)
+}
@@ -8,7 +8,7 @@ import (
 
 func RSA_PKCS() {
 	//key gen
-	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	privateKey, err := generateKey(nil, 2048)
 	if err != nil {
 		fmt.Println("keygen err: ", err.Error())
 		return